@@ -3,10 +3,41 @@ package what3words
 import (
 	"context"
 	"fmt"
+	"math"
+	"net/url"
 	"strconv"
 	"strings"
 )
 
+const (
+	// _maxNResults is the maximum number of AutoSuggest results the API can return.
+	_maxNResults = 100
+
+	// _defaultNResults is the number of AutoSuggest results returned when n-results is not specified.
+	_defaultNResults = 3
+)
+
+// InputType specifies the type of input passed to AutoSuggest.
+type InputType string
+
+// Input types supported by AutoSuggest. Voice input types accept the output of a speech recogniser
+// and require a language to be set.
+const (
+	InputTypeText         InputType = "text"
+	InputTypeVoconHybrid  InputType = "vocon-hybrid"
+	InputTypeNMDPASR      InputType = "nmdp-asr"
+	InputTypeGenericVoice InputType = "generic-voice"
+)
+
+// Valid reports whether the InputType is one of the input types supported by AutoSuggest.
+func (t InputType) Valid() bool {
+	switch t {
+	case InputTypeText, InputTypeVoconHybrid, InputTypeNMDPASR, InputTypeGenericVoice:
+		return true
+	}
+	return false
+}
+
 // AutoSuggestResponse contains suggestions.
 type AutoSuggestResponse struct {
 	Suggestions []Suggestion `json:"suggestions"`
@@ -31,6 +62,29 @@ type Suggestion struct {
 
 	// Language is the language in which the suggested 3 word address is given.
 	Language string `json:"language,omitempty"`
+
+	// Locale is the locale of the suggested 3 word address, only returned when a locale was requested.
+	Locale string `json:"locale,omitempty"`
+}
+
+// AutoSuggestWithCoordinatesResponse contains suggestions including their coordinates.
+type AutoSuggestWithCoordinatesResponse struct {
+	Suggestions []SuggestionWithCoordinates `json:"suggestions"`
+}
+
+// SuggestionWithCoordinates is a Suggestion from the AutoSuggest with coordinates API,
+// which also contains the coordinates, grid square and map link of the suggested 3 word address.
+type SuggestionWithCoordinates struct {
+	Suggestion
+
+	// Coordinates is the centre of the grid square of the suggested 3 word address.
+	Coordinates Coordinates `json:"coordinates"`
+
+	// Square is the bounds of the grid square of the suggested 3 word address.
+	Square Square `json:"square"`
+
+	// Map is a link to the suggested 3 word address on the What3Words map site.
+	Map string `json:"map,omitempty"`
 }
 
 // AutoSuggestInput contains the required and optional parameters for performing an AutoSuggestion request
@@ -49,7 +103,7 @@ type AutoSuggestInput struct {
 	// clipToPolygon: Restrict AutoSuggest results to a polygon, specified by a comma-separated list of lat,lng pairs.
 	// The polygon should be closed, i.e. the first element should be repeated as the last
 	// element; also the list should contain at least 4 entries.
-	// The API is currently limited to accepting up to 25 pairs.
+	// The API is currently limited to accepting up to 25 pairs. See PolygonCoordinates.Validate.
 	ClipToPolygon PolygonCoordinates
 
 	// focus: This is a location, specified as latitude,longitude.
@@ -57,10 +111,25 @@ type AutoSuggestInput struct {
 	// For convenience, longitude is allowed to wrap around the 180 line, so 361 is equivalent to 1.
 	Focus *Coordinates
 
+	// InputType: The type of input, defaults to text. Voice input types allow AutoSuggest to accept
+	// the output of a speech recogniser, such as "filled count soap".
+	InputType InputType
+
 	// Language: For normal text input, specifies a fallback language,
-	// which will help guide AutoSuggest if the input is particularly messy
+	// which will help guide AutoSuggest if the input is particularly messy.
+	// For voice input this is the language the input was spoken in. Defaults to the client language.
 	Language string
 
+	// Locale: Specifies the locale of the input for languages with multiple scripts, e.g. mn_la or mn_cy.
+	Locale string
+
+	// NFocusResults: The number of results within NResults which must be close to the focus.
+	// Requires Focus and must not be greater than NResults.
+	NFocusResults int
+
+	// NResults: The number of AutoSuggest results to return, up to 100. The API defaults to 3.
+	NResults int
+
 	// PreferLand: Makes AutoSuggest prefer results on land to those in the sea.
 	// This setting is on by default. Use false to disable this setting and receive more suggestions in the sea.
 	PreferLand *bool
@@ -72,32 +141,193 @@ type AutoSuggestInput struct {
 }
 
 // AutoSuggest Returns a list of 3 word addresses based on user input and other parameters.
-func (w *w3w) AutoSuggest(ctx context.Context, input *AutoSuggestInput) (*AutoSuggestResponse, error) {
-	u := w.endpoint.JoinPath("/autosuggest")
-	query := u.Query()
+func (w *w3w) AutoSuggest(ctx context.Context, input *AutoSuggestInput, opts ...CallOption) (*AutoSuggestResponse, error) {
+	if input == nil {
+		return nil, fmt.Errorf("retrieving auto suggestion: %w", &ValidationError{Field: "input", Message: "must be specified"})
+	}
+
+	c := w.newCall(opts)
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	query, err := input.query(c)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving auto suggestion: %w", err)
+	}
+
+	u := w.autoSuggestURL("autosuggest", query)
+
+	var resp AutoSuggestResponse
+	if err := w.request(ctx, u, c, &resp); err != nil {
+		return nil, fmt.Errorf("retrieving auto suggestion: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// AutoSuggestWithCoordinates Returns a list of 3 word addresses based on user input and other parameters,
+// including the coordinates and grid square of every suggestion. Each suggestion counts as a convert to coordinates
+// request towards your plan's quota.
+func (w *w3w) AutoSuggestWithCoordinates(ctx context.Context, input *AutoSuggestInput, opts ...CallOption) (*AutoSuggestWithCoordinatesResponse, error) {
+	if input == nil {
+		return nil, fmt.Errorf("retrieving auto suggestion with coordinates: %w", &ValidationError{Field: "input", Message: "must be specified"})
+	}
+
+	c := w.newCall(opts)
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	query, err := input.query(c)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving auto suggestion with coordinates: %w", err)
+	}
+
+	u := w.autoSuggestURL("autosuggest-with-coordinates", query)
+
+	var resp AutoSuggestWithCoordinatesResponse
+	if err := w.request(ctx, u, c, &resp); err != nil {
+		return nil, fmt.Errorf("retrieving auto suggestion with coordinates: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// ReportSelection reports which suggestion the user selected from the results of an AutoSuggest request,
+// which helps what3words improve the ranking of suggestions for your account. The input must be the
+// AutoSuggestInput used for the original request and rank the 1-based position the selection was shown in.
+// ReportSelection does not modify its arguments, so it is safe to call in a goroutine once the results have been shown.
+func (w *w3w) ReportSelection(ctx context.Context, input *AutoSuggestInput, selection Suggestion, rank int, opts ...CallOption) error {
+	if input == nil {
+		return fmt.Errorf("reporting auto suggest selection: %w", &ValidationError{Field: "input", Message: "must be specified"})
+	}
+
+	c := w.newCall(opts)
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	query, err := input.query(c)
+	if err != nil {
+		return fmt.Errorf("reporting auto suggest selection: %w", err)
+	}
+
+	if rank < 1 {
+		return fmt.Errorf("reporting auto suggest selection: %w", &ValidationError{Field: "rank", Message: "must be 1 or greater"})
+	}
+
+	sourceAPI := "text"
+	if input.InputType != "" && input.InputType != InputTypeText {
+		sourceAPI = "voice"
+	}
+
+	query.Del("input")
+	query.Set("raw-input", input.Words)
+	query.Set("selection", selection.Words)
+	query.Set("rank", strconv.Itoa(rank))
+	query.Set("source-api", sourceAPI)
+
+	u := w.autoSuggestURL("autosuggest-selection", query)
+
+	if err := w.request(ctx, u, c, nil); err != nil {
+		return fmt.Errorf("reporting auto suggest selection: %w", err)
+	}
+
+	return nil
+}
+
+// autoSuggestURL returns the URL of the named autosuggest endpoint with the query parameters added to any query
+// parameters of the client endpoint, in the same way as the other endpoints.
+func (w *w3w) autoSuggestURL(name string, query url.Values) *url.URL {
+	u := w.endpointURL(name)
+	merged := u.Query()
+	for key, values := range query {
+		merged[key] = values
+	}
+	u.RawQuery = merged.Encode()
+	return u
+}
+
+// query validates the AutoSuggestInput and builds the query parameters for an AutoSuggest request,
+// falling back to the language and locale of the call when the input does not specify them.
+func (input *AutoSuggestInput) query(c *call) (url.Values, error) {
+	if (input.InputType == "" || input.InputType == InputTypeText) && !IsPossible3wa(input.Words) {
+		return nil, &ValidationError{
+			Field:   "input",
+			Message: fmt.Sprintf("%q must contain two words and at least one character of the third word", input.Words),
+		}
+	}
+
+	query := url.Values{}
 	query.Set("input", input.Words)
 
+	language := c.language
 	if input.Language != "" {
-		query.Set("language", w.language)
-	} else {
-		query.Set("language", input.Language)
+		language = input.Language
+	}
+	query.Set("language", language)
+
+	locale := c.locale
+	if input.Locale != "" {
+		locale = input.Locale
+	}
+	if locale != "" {
+		query.Set("locale", locale)
+	}
+
+	if input.InputType != "" {
+		if !input.InputType.Valid() {
+			return nil, &ValidationError{Field: "input-type", Message: fmt.Sprintf("unsupported input type %q", input.InputType)}
+		}
+		query.Set("input-type", string(input.InputType))
+	}
+
+	if input.NResults != 0 {
+		if input.NResults < 1 || input.NResults > _maxNResults {
+			return nil, &ValidationError{Field: "n-results", Message: fmt.Sprintf("must be between 1 and %d", _maxNResults)}
+		}
+		query.Set("n-results", strconv.Itoa(input.NResults))
+	}
+
+	if input.NFocusResults != 0 {
+		nResults := input.NResults
+		if nResults == 0 {
+			nResults = _defaultNResults
+		}
+
+		switch {
+		case input.Focus == nil:
+			return nil, &ValidationError{Field: "n-focus-results", Message: "requires focus to be set"}
+		case input.NFocusResults < 1 || input.NFocusResults > nResults:
+			return nil, &ValidationError{Field: "n-focus-results", Message: fmt.Sprintf("must be between 1 and n-results (%d)", nResults)}
+		}
+		query.Set("n-focus-results", strconv.Itoa(input.NFocusResults))
 	}
 
 	if input.Focus != nil {
+		// The longitude of the focus may wrap around the 180 line.
+		focus := Coordinates{Lat: input.Focus.Lat, Lng: math.Remainder(input.Focus.Lng, 360)}
+		if err := focus.validate("focus"); err != nil {
+			return nil, err
+		}
 		query.Set("focus", input.Focus.ToString())
 	}
 
 	if input.ClipToBoundingBox != nil {
+		if err := input.ClipToBoundingBox.validate("clip-to-bounding-box"); err != nil {
+			return nil, err
+		}
 		query.Set("clip-to-bounding-box", input.ClipToBoundingBox.ToString())
 	}
 
 	if input.ClipToCircle != nil {
+		if err := input.ClipToCircle.validate("clip-to-circle"); err != nil {
+			return nil, err
+		}
 		query.Set("clip-to-circle", input.ClipToCircle.ToString())
 	}
 
 	if input.ClipToPolygon != nil {
-		if len(input.ClipToPolygon) > 24 {
-			return nil, fmt.Errorf("clip to polygon is limited to 25 coordinate pairs")
+		if err := input.ClipToPolygon.validate("clip-to-polygon"); err != nil {
+			return nil, err
 		}
 		query.Set("clip-to-polygon", input.ClipToPolygon.ToString())
 	}
@@ -106,19 +336,11 @@ func (w *w3w) AutoSuggest(ctx context.Context, input *AutoSuggestInput) (*AutoSu
 		query.Set("clip-to-country", strings.Join(input.ClipToCountry, ","))
 	}
 
-	if input.PreferLand == nil {
-		preferLand := true
-		input.PreferLand = &preferLand
+	preferLand := true
+	if input.PreferLand != nil {
+		preferLand = *input.PreferLand
 	}
+	query.Set("prefer-land", strconv.FormatBool(preferLand))
 
-	query.Set("prefer-land", strconv.FormatBool(*input.PreferLand))
-
-	u.RawQuery = query.Encode()
-
-	var resp AutoSuggestResponse
-	if err := w.request(ctx, u.String(), &resp); err != nil {
-		return nil, fmt.Errorf("retrieving auto suggestion: %w", err)
-	}
-
-	return &resp, nil
+	return query, nil
 }
@@ -394,6 +394,9 @@ func TestW3w_AutoSuggest(t *testing.T) {
 				Suggestions: []Suggestion{},
 			},
 		},
+		"missing input is rejected before sending": {
+			expectedError: "retrieving auto suggestion: invalid input: must be specified",
+		},
 		"error making auto suggest": {
 			input: &AutoSuggestInput{
 				Words: "",
@@ -435,3 +438,393 @@ func TestW3w_AutoSuggest(t *testing.T) {
 		})
 	}
 }
+
+func TestAutoSuggestInput_Query(t *testing.T) {
+	preferLand := false
+
+	tests := map[string]struct {
+		input         *AutoSuggestInput
+		expected      url.Values
+		expectedError string
+	}{
+		"defaults to client language and prefer land": {
+			input: &AutoSuggestInput{
+				Words: "filled.count.so",
+			},
+			expected: url.Values{
+				"input":       {"filled.count.so"},
+				"language":    {"en"},
+				"prefer-land": {"true"},
+			},
+		},
+		"input language overrides client language": {
+			input: &AutoSuggestInput{
+				Words:      "filled.count.so",
+				Language:   "de",
+				PreferLand: &preferLand,
+			},
+			expected: url.Values{
+				"input":       {"filled.count.so"},
+				"language":    {"de"},
+				"prefer-land": {"false"},
+			},
+		},
+		"voice input with locale and result counts": {
+			input: &AutoSuggestInput{
+				Words:         "filled count soap",
+				InputType:     InputTypeGenericVoice,
+				Language:      "mn",
+				Locale:        "mn_la",
+				NResults:      10,
+				NFocusResults: 5,
+				Focus: &Coordinates{
+					Lat: 51.521251,
+					Lng: -0.203607,
+				},
+			},
+			expected: url.Values{
+				"input":           {"filled count soap"},
+				"input-type":      {"generic-voice"},
+				"language":        {"mn"},
+				"locale":          {"mn_la"},
+				"n-results":       {"10"},
+				"n-focus-results": {"5"},
+				"focus":           {"51.521251,-0.203607"},
+				"prefer-land":     {"true"},
+			},
+		},
+		"focus longitude wrapping around the 180 line": {
+			input: &AutoSuggestInput{
+				Words: "filled.count.so",
+				Focus: &Coordinates{Lat: 51.521251, Lng: 359.796393},
+			},
+			expected: url.Values{
+				"input":       {"filled.count.so"},
+				"language":    {"en"},
+				"focus":       {"51.521251,359.796393"},
+				"prefer-land": {"true"},
+			},
+		},
+		"clip to polygon of 25 coordinates": {
+			input: &AutoSuggestInput{
+				Words:         "filled.count.so",
+				ClipToPolygon: closedPolygon(25),
+			},
+			expected: url.Values{
+				"input":           {"filled.count.so"},
+				"language":        {"en"},
+				"clip-to-polygon": {closedPolygon(25).ToString()},
+				"prefer-land":     {"true"},
+			},
+		},
+		"clip to polygon of 26 coordinates": {
+			input: &AutoSuggestInput{
+				Words:         "filled.count.so",
+				ClipToPolygon: closedPolygon(26),
+			},
+			expectedError: "invalid clip-to-polygon: is limited to 25 coordinates, got 26",
+		},
+		"focus latitude out of range": {
+			input: &AutoSuggestInput{
+				Words: "filled.count.so",
+				Focus: &Coordinates{Lat: 151.521251, Lng: -0.203607},
+			},
+			expectedError: "invalid focus: latitude 151.521251 must be between -90 and 90",
+		},
+		"clip to circle without radius": {
+			input: &AutoSuggestInput{
+				Words:        "filled.count.so",
+				ClipToCircle: &CoordinateRadius{Coordinates: Coordinates{Lat: 51.521251, Lng: -0.203607}},
+			},
+			expectedError: "invalid clip-to-circle: radius 0 must be greater than 0",
+		},
+		"clip to bounding box with south above north": {
+			input: &AutoSuggestInput{
+				Words:             "filled.count.so",
+				ClipToBoundingBox: NewBoundingBox(52, -1, 51, 0),
+			},
+			expectedError: "invalid clip-to-bounding-box: south latitude 52 must not be greater than north latitude 51",
+		},
+		"incomplete text input": {
+			input: &AutoSuggestInput{
+				Words: "filled.count.",
+			},
+			expectedError: `invalid input: "filled.count." must contain two words and at least one character of the third word`,
+		},
+		"unsupported input type": {
+			input: &AutoSuggestInput{
+				Words:     "filled.count.so",
+				InputType: "morse",
+			},
+			expectedError: `invalid input-type: unsupported input type "morse"`,
+		},
+		"too many results": {
+			input: &AutoSuggestInput{
+				Words:    "filled.count.so",
+				NResults: 101,
+			},
+			expectedError: "invalid n-results: must be between 1 and 100",
+		},
+		"focus results without focus": {
+			input: &AutoSuggestInput{
+				Words:         "filled.count.so",
+				NFocusResults: 1,
+			},
+			expectedError: "invalid n-focus-results: requires focus to be set",
+		},
+		"focus results greater than default results": {
+			input: &AutoSuggestInput{
+				Words:         "filled.count.so",
+				NFocusResults: 4,
+				Focus:         &Coordinates{Lat: 51.521251, Lng: -0.203607},
+			},
+			expectedError: "invalid n-focus-results: must be between 1 and n-results (3)",
+		},
+		"focus results greater than results": {
+			input: &AutoSuggestInput{
+				Words:         "filled.count.so",
+				NResults:      5,
+				NFocusResults: 6,
+				Focus:         &Coordinates{Lat: 51.521251, Lng: -0.203607},
+			},
+			expectedError: "invalid n-focus-results: must be between 1 and n-results (5)",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.input.query(&call{language: "en"})
+			if tt.expectedError != "" {
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.Equal(t, tt.expected, got)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestW3w_AutoSuggestWithCoordinates(t *testing.T) {
+	type response struct {
+		statusCode int
+		body       []byte
+	}
+
+	tests := map[string]struct {
+		expectedError string
+		expected      *AutoSuggestWithCoordinatesResponse
+		input         *AutoSuggestInput
+		response      response
+	}{
+		"auto suggest with coordinates": {
+			input: &AutoSuggestInput{
+				Words: "filled.count.soa",
+			},
+			response: response{
+				statusCode: http.StatusOK,
+				body: []byte(`{
+					"suggestions": [
+						{
+							"country": "GB",
+							"nearestPlace": "Bayswater, London",
+							"words": "filled.count.soap",
+							"rank": 1,
+							"language": "en",
+							"coordinates": {
+								"lng": -0.195521,
+								"lat": 51.520847
+							},
+							"square": {
+								"southwest": {
+									"lng": -0.195543,
+									"lat": 51.520833
+								},
+								"northeast": {
+									"lng": -0.195499,
+									"lat": 51.52086
+								}
+							},
+							"map": "https://w3w.co/filled.count.soap"
+						}
+					]
+				}`),
+			},
+			expected: &AutoSuggestWithCoordinatesResponse{
+				Suggestions: []SuggestionWithCoordinates{
+					{
+						Suggestion: Suggestion{
+							Country:      "GB",
+							NearestPlace: "Bayswater, London",
+							Words:        "filled.count.soap",
+							Rank:         1,
+							Language:     "en",
+						},
+						Coordinates: Coordinates{
+							Lat: 51.520847,
+							Lng: -0.195521,
+						},
+						Square: Square{
+							Southwest: Coordinates{
+								Lat: 51.520833,
+								Lng: -0.195543,
+							},
+							Northeast: Coordinates{
+								Lat: 51.52086,
+								Lng: -0.195499,
+							},
+						},
+						Map: "https://w3w.co/filled.count.soap",
+					},
+				},
+			},
+		},
+		"invalid input is rejected before sending": {
+			input: &AutoSuggestInput{
+				Words:    "filled.count.soa",
+				NResults: 200,
+			},
+			expectedError: "retrieving auto suggestion with coordinates: invalid n-results",
+		},
+		"missing input is rejected before sending": {
+			expectedError: "retrieving auto suggestion with coordinates: invalid input: must be specified",
+		},
+		"error making auto suggest with coordinates": {
+			input: &AutoSuggestInput{
+				Words: "filled.count.soa",
+			},
+			response: response{
+				statusCode: http.StatusBadRequest,
+				body: []byte(`{
+					"error": {
+						"code": "BadClipToCountry",
+						"message": "Invalid clip-to-country"
+					}
+				}`),
+			},
+			expectedError: "retrieving auto suggestion with coordinates",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/autosuggest-with-coordinates", r.URL.Path)
+				rw.Header().Set("Content-Type", "application/json")
+				rw.WriteHeader(tt.response.statusCode)
+				_, err := rw.Write(tt.response.body)
+				assert.NoError(t, err)
+			}))
+			defer ts.Close()
+
+			u, err := url.Parse(ts.URL)
+			assert.NoError(t, err)
+
+			w := NewClient("example-api-key", WithEndpoint(u))
+			got, err := w.AutoSuggestWithCoordinates(context.Background(), tt.input)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+			} else {
+				assert.Equal(t, tt.expected, got)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestW3w_ReportSelection(t *testing.T) {
+	tests := map[string]struct {
+		expectedError string
+		expectedQuery url.Values
+		input         *AutoSuggestInput
+		selection     Suggestion
+		rank          int
+		statusCode    int
+	}{
+		"report text selection": {
+			input: &AutoSuggestInput{
+				Words:         "plan.clips.a",
+				ClipToCountry: []string{"GB"},
+			},
+			selection: Suggestion{
+				Words: "plan.clips.area",
+				Rank:  1,
+			},
+			rank:       1,
+			statusCode: http.StatusOK,
+			expectedQuery: url.Values{
+				"raw-input":       {"plan.clips.a"},
+				"selection":       {"plan.clips.area"},
+				"rank":            {"1"},
+				"source-api":      {"text"},
+				"language":        {"en"},
+				"clip-to-country": {"GB"},
+				"prefer-land":     {"true"},
+			},
+		},
+		"report voice selection": {
+			input: &AutoSuggestInput{
+				Words:     "plan clips area",
+				InputType: InputTypeGenericVoice,
+				Language:  "en",
+			},
+			selection: Suggestion{
+				Words: "plan.clips.arts",
+			},
+			rank:       3,
+			statusCode: http.StatusOK,
+			expectedQuery: url.Values{
+				"raw-input":   {"plan clips area"},
+				"selection":   {"plan.clips.arts"},
+				"rank":        {"3"},
+				"source-api":  {"voice"},
+				"input-type":  {"generic-voice"},
+				"language":    {"en"},
+				"prefer-land": {"true"},
+			},
+		},
+		"missing input": {
+			selection:     Suggestion{Words: "plan.clips.area"},
+			rank:          1,
+			expectedError: "reporting auto suggest selection: invalid input: must be specified",
+		},
+		"invalid rank": {
+			input: &AutoSuggestInput{
+				Words: "plan.clips.a",
+			},
+			selection:     Suggestion{Words: "plan.clips.area"},
+			expectedError: "reporting auto suggest selection: invalid rank",
+		},
+		"error reporting selection": {
+			input: &AutoSuggestInput{
+				Words: "plan.clips.a",
+			},
+			selection:     Suggestion{Words: "plan.clips.area"},
+			rank:          1,
+			statusCode:    http.StatusBadRequest,
+			expectedError: "reporting auto suggest selection",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/autosuggest-selection", r.URL.Path)
+				if tt.expectedQuery != nil {
+					assert.Equal(t, tt.expectedQuery, r.URL.Query())
+				}
+				rw.WriteHeader(tt.statusCode)
+			}))
+			defer ts.Close()
+
+			u, err := url.Parse(ts.URL)
+			assert.NoError(t, err)
+
+			w := NewClient("example-api-key", WithEndpoint(u))
+			err = w.ReportSelection(context.Background(), tt.input, tt.selection, tt.rank)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package what3words
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// _defaultBatchConcurrency is the number of requests a batch makes concurrently if BatchOptions.Concurrency is not set.
+const _defaultBatchConcurrency = 10
+
+// BatchOptions configures a batch conversion.
+type BatchOptions struct {
+	// Concurrency is the maximum number of requests in flight at once. Defaults to 10.
+	Concurrency int
+
+	// Progress is called each time an input has been converted, successfully or not, with the number
+	// of inputs completed so far and the total. Calls are never made concurrently.
+	Progress func(completed, total int)
+}
+
+// BatchResult is the result of converting a single input in a batch.
+type BatchResult struct {
+	// Response is the converted location, or nil if the conversion failed.
+	Response *LocationResponse
+
+	// Err is the error converting the input, if any.
+	Err error
+}
+
+// BatchConvertTo3wa converts each of the coordinates to a 3 word address, making up to BatchOptions.Concurrency
+// requests at once. It returns one result per input in input order, each with its own error, so a single bad
+// input does not fail the whole batch. If the context is done before the batch completes, the results converted
+// so far are returned along with the context error, and the remaining results contain the context error.
+func (w *w3w) BatchConvertTo3wa(ctx context.Context, coordinates []Coordinates, opts BatchOptions, callOpts ...CallOption) ([]BatchResult, error) {
+	results, err := batch(ctx, len(coordinates), opts, func(ctx context.Context, i int) (*LocationResponse, error) {
+		return w.ConvertTo3wa(ctx, &coordinates[i], callOpts...)
+	})
+	if err != nil {
+		return results, fmt.Errorf("batch converting coordinates to 3 Word Address: %w", err)
+	}
+
+	return results, nil
+}
+
+// BatchConvertToCoordinates converts each of the 3 word addresses to coordinates, making up to
+// BatchOptions.Concurrency requests at once. Results are returned in the same way as BatchConvertTo3wa.
+func (w *w3w) BatchConvertToCoordinates(ctx context.Context, words []string, opts BatchOptions, callOpts ...CallOption) ([]BatchResult, error) {
+	results, err := batch(ctx, len(words), opts, func(ctx context.Context, i int) (*LocationResponse, error) {
+		return w.ConvertToCoordinates(ctx, words[i], callOpts...)
+	})
+	if err != nil {
+		return results, fmt.Errorf("batch converting w3w to coordinates: %w", err)
+	}
+
+	return results, nil
+}
+
+// batch calls convert for each index in [0, n) using a bounded pool of workers.
+func batch(ctx context.Context, n int, opts BatchOptions, convert func(ctx context.Context, i int) (*LocationResponse, error)) ([]BatchResult, error) {
+	results := make([]BatchResult, n)
+	next := forEach(ctx, n, opts, func(ctx context.Context, i int) {
+		resp, err := convert(ctx, i)
+		results[i] = BatchResult{Response: resp, Err: err}
+	})
+	if next == n {
+		return results, nil
+	}
+
+	for i := next; i < n; i++ {
+		results[i] = BatchResult{Err: ctx.Err()}
+	}
+	return results, ctx.Err()
+}
+
+// forEach calls do for each index in [0, n) using a pool of up to BatchOptions.Concurrency workers, reporting
+// progress after each call. Indexes stop being dispatched once the context is done, and the number of indexes
+// dispatched is returned once every call has returned.
+func forEach(ctx context.Context, n int, opts BatchOptions, do func(ctx context.Context, i int)) int {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = _defaultBatchConcurrency
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	indexes := make(chan int)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		completed int
+	)
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				do(ctx, i)
+
+				mu.Lock()
+				completed++
+				if opts.Progress != nil {
+					opts.Progress(completed, n)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	next := 0
+dispatch:
+	for ; next < n; next++ {
+		select {
+		case indexes <- next:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return next
+}
@@ -0,0 +1,119 @@
+package what3words
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestW3w_BatchConvertToCoordinates(t *testing.T) {
+	var inFlight, maxInFlight int32
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			peak := atomic.LoadInt32(&maxInFlight)
+			if current <= peak || atomic.CompareAndSwapInt32(&maxInFlight, peak, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		words := r.URL.Query().Get("words")
+		if words == "bad.words.here" {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, err := rw.Write([]byte(`{"error": {"code": "BadWords", "message": "invalid words"}}`))
+			assert.NoError(t, err)
+			return
+		}
+
+		_, err := fmt.Fprintf(rw, `{"words": %q, "language": "en"}`, words)
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	words := []string{"filled.count.soap", "bad.words.here", "index.home.raft", "daring.lion.race", "not-a-3wa"}
+	for i := 0; i < 20; i++ {
+		words = append(words, "filled.count.soap")
+	}
+
+	var progress []int
+	w := NewClient("example-api-key", WithEndpoint(u))
+	results, err := w.BatchConvertToCoordinates(context.Background(), words, BatchOptions{
+		Concurrency: 3,
+		Progress: func(completed, total int) {
+			assert.Equal(t, len(words), total)
+			progress = append(progress, completed)
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, len(words))
+
+	for i, result := range results {
+		switch words[i] {
+		case "bad.words.here":
+			assert.ErrorIs(t, result.Err, ErrBadWords)
+			assert.Nil(t, result.Response)
+		case "not-a-3wa":
+			var validationErr *ValidationError
+			assert.ErrorAs(t, result.Err, &validationErr)
+		default:
+			assert.NoError(t, result.Err)
+			assert.Equal(t, words[i], result.Response.Words)
+		}
+	}
+
+	assert.Len(t, progress, len(words))
+	assert.Equal(t, len(words), progress[len(progress)-1])
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(3))
+}
+
+func TestW3w_BatchConvertTo3waCancelled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		_, err := rw.Write([]byte(`{"words": "filled.count.soap", "language": "en"}`))
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	coordinates := make([]Coordinates, 100)
+	for i := range coordinates {
+		coordinates[i] = Coordinates{Lat: 51.520847, Lng: -0.195521 + float64(i)*0.001}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := NewClient("example-api-key", WithEndpoint(u))
+	results, err := w.BatchConvertTo3wa(ctx, coordinates, BatchOptions{
+		Concurrency: 1,
+		Progress: func(completed, total int) {
+			if completed == 10 {
+				cancel()
+			}
+		},
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, results, len(coordinates))
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, results[i].Err)
+		assert.Equal(t, "filled.count.soap", results[i].Response.Words)
+	}
+	for _, result := range results[11:] {
+		assert.ErrorIs(t, result.Err, context.Canceled)
+		assert.Nil(t, result.Response)
+	}
+}
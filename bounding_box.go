@@ -17,6 +17,26 @@ func (b BoundingBox) ToString() string {
 	return fmt.Sprintf("%f,%f,%f,%f", b.SouthLat, b.WestLng, b.NorthLat, b.EastLng)
 }
 
+// Validate checks that the corners of the box are valid coordinates and that the southern latitude is not above the
+// northern latitude. The western longitude may be greater than the eastern longitude for boxes which cross the
+// antimeridian. It returns a *ValidationError describing the first problem found.
+func (b BoundingBox) Validate() error {
+	return b.validate("bounding-box")
+}
+
+func (b BoundingBox) validate(field string) error {
+	if err := (Coordinates{Lat: b.SouthLat, Lng: b.WestLng}).validate(field); err != nil {
+		return err
+	}
+	if err := (Coordinates{Lat: b.NorthLat, Lng: b.EastLng}).validate(field); err != nil {
+		return err
+	}
+	if b.SouthLat > b.NorthLat {
+		return &ValidationError{Field: field, Message: fmt.Sprintf("south latitude %g must not be greater than north latitude %g", b.SouthLat, b.NorthLat)}
+	}
+	return nil
+}
+
 // NewBoundingBox constructs a BoundingBox
 func NewBoundingBox(southLat, westLng, northLat, eastLng float64) *BoundingBox {
 	return &BoundingBox{
@@ -0,0 +1,276 @@
+package what3words
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// _conversionCacheTTL is how long conversions between coordinates and 3 word addresses are cached for.
+	_conversionCacheTTL = 24 * time.Hour
+
+	// _languagesCacheTTL is how long the available languages are cached for.
+	_languagesCacheTTL = 7 * 24 * time.Hour
+
+	// _squareCellSize is the size in degrees of the cells used to index cached grid squares by location.
+	// A cell is larger than a grid square, so a square overlaps at most 4 cells.
+	_squareCellSize = 0.0001
+
+	// _maxSquaresPerCell limits the number of grid squares cached for a single cell.
+	_maxSquaresPerCell = 32
+)
+
+// Cache stores API responses so that repeated lookups can be served without a request.
+// Implementations must be safe for concurrent use. Values are JSON encoded responses, which allows
+// shared caches such as Redis or memcached to be used by implementing this interface.
+type Cache interface {
+	// Get returns the value stored for the key, or false if it is missing or has expired.
+	Get(key string) ([]byte, bool)
+
+	// Set stores the value for the key. A ttl of zero or less means the value does not expire.
+	Set(key string, value []byte, ttl time.Duration)
+
+	// Delete removes the value stored for the key.
+	Delete(key string)
+}
+
+// CacheStats contains the number of cache hits and misses for a client.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// WithCache is a Functional Option for caching the responses of ConvertToCoordinates, ConvertTo3wa and
+// AvailableLanguages. Conversions are cached by normalised words and language, or by the grid square containing
+// the coordinates, so any point inside a cached square is served without a request.
+func WithCache(cache Cache) Option {
+	return func(w *w3w) {
+		w.cache = cache
+	}
+}
+
+// CacheStats returns the number of cache hits and misses since the client was created.
+func (w *w3w) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:   w.cacheHits.Load(),
+		Misses: w.cacheMisses.Load(),
+	}
+}
+
+// cacheGet decodes the cached value for the key into out, recording a hit or miss.
+func (w *w3w) cacheGet(key string, out interface{}) bool {
+	if w.cache == nil {
+		return false
+	}
+
+	value, ok := w.cache.Get(key)
+	if ok && json.Unmarshal(value, out) == nil {
+		w.cacheHits.Add(1)
+		return true
+	}
+
+	w.cacheMisses.Add(1)
+	return false
+}
+
+// cacheSet stores the JSON encoding of value for the key.
+func (w *w3w) cacheSet(key string, value interface{}, ttl time.Duration) {
+	if w.cache == nil {
+		return
+	}
+
+	if b, err := json.Marshal(value); err == nil {
+		w.cache.Set(key, b, ttl)
+	}
+}
+
+// cachedSquare returns the cached location whose grid square contains the coordinates.
+func (w *w3w) cachedSquare(language string, coordinates Coordinates) (*LocationResponse, bool) {
+	if w.cache == nil {
+		return nil, false
+	}
+
+	var squares []LocationResponse
+	if value, ok := w.cache.Get(squareCellKey(language, coordinates)); ok && json.Unmarshal(value, &squares) == nil {
+		for i := range squares {
+			if squares[i].Square.Contains(coordinates) {
+				w.cacheHits.Add(1)
+				return &squares[i], true
+			}
+		}
+	}
+
+	w.cacheMisses.Add(1)
+	return nil, false
+}
+
+// cacheLocation stores a location by its words in the requested language and by every cell its grid square overlaps
+// in the language or locale of its words, so that it can be found by either ConvertToCoordinates or ConvertTo3wa.
+func (w *w3w) cacheLocation(language, squareLanguage string, resp *LocationResponse) {
+	if w.cache == nil {
+		return
+	}
+
+	w.cacheSet(wordsCacheKey(language, resp.Words), resp, _conversionCacheTTL)
+
+	sw, ne := resp.Square.Southwest, resp.Square.Northeast
+	corners := []Coordinates{sw, {Lat: sw.Lat, Lng: ne.Lng}, ne, {Lat: ne.Lat, Lng: sw.Lng}}
+
+	seen := make(map[string]bool, len(corners))
+	for _, corner := range corners {
+		key := squareCellKey(squareLanguage, corner)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		var squares []LocationResponse
+		if value, ok := w.cache.Get(key); ok {
+			_ = json.Unmarshal(value, &squares)
+		}
+
+		squares = append(withoutWords(squares, resp.Words), *resp)
+		if len(squares) > _maxSquaresPerCell {
+			squares = squares[len(squares)-_maxSquaresPerCell:]
+		}
+		w.cacheSet(key, squares, _conversionCacheTTL)
+	}
+}
+
+// wordsLanguage returns the language or locale of the words of a location returned for the call. Words given to
+// ConvertToCoordinates may be in any language, so this is the language or locale of the response, except that the
+// locale of the call is used for words in the language of the call, as the API does not always return the locale.
+func (c *call) wordsLanguage(resp *LocationResponse) string {
+	switch {
+	case resp.Locale != "":
+		return resp.Locale
+	case resp.Language == c.language:
+		return c.cacheLanguage()
+	default:
+		return resp.Language
+	}
+}
+
+func withoutWords(squares []LocationResponse, words string) []LocationResponse {
+	filtered := squares[:0]
+	for _, s := range squares {
+		if s.Words != words {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
+// normaliseWords returns the canonical form of a 3 word address used for cache keys.
+func normaliseWords(words string) string {
+	return strings.ToLower(strings.TrimLeft(strings.TrimSpace(words), "/"))
+}
+
+func wordsCacheKey(language, words string) string {
+	return fmt.Sprintf("convert-to-coordinates:%s:%s", language, normaliseWords(words))
+}
+
+func squareCellKey(language string, c Coordinates) string {
+	return fmt.Sprintf("convert-to-3wa:%s:%d:%d", language,
+		int64(math.Floor(c.Lat/_squareCellSize)), int64(math.Floor(c.Lng/_squareCellSize)))
+}
+
+// LRUCache is an in-memory Cache which evicts the least recently used entry once it holds the maximum number
+// of entries. Expired entries are removed when they are next read. LRUCache is safe for concurrent use.
+type LRUCache struct {
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries *list.List
+	items   map[string]*list.Element
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRUCache creates an LRUCache holding up to maxEntries entries. A maxEntries of zero or less means no limit.
+func NewLRUCache(maxEntries int) *LRUCache {
+	return &LRUCache{
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Get returns the value stored for the key, or false if it is missing or has expired.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !entry.expires.IsZero() && !c.now().Before(entry.expires) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.entries.MoveToFront(element)
+	return entry.value, true
+}
+
+// Set stores the value for the key, evicting the least recently used entry if the cache is full.
+// A ttl of zero or less means the value does not expire.
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = c.now().Add(ttl)
+	}
+
+	if element, ok := c.items[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value, entry.expires = value, expires
+		c.entries.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.entries.PushFront(&lruEntry{key: key, value: value, expires: expires})
+
+	if c.maxEntries > 0 && c.entries.Len() > c.maxEntries {
+		c.remove(c.entries.Back())
+	}
+}
+
+// Delete removes the value stored for the key.
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.remove(element)
+	}
+}
+
+// Len returns the number of entries in the cache, including expired entries which have not yet been removed.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entries.Len()
+}
+
+func (c *LRUCache) remove(element *list.Element) {
+	c.entries.Remove(element)
+	delete(c.items, element.Value.(*lruEntry).key)
+}
@@ -0,0 +1,259 @@
+package what3words
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCache(t *testing.T) {
+	type operation struct {
+		set     string
+		ttl     time.Duration
+		get     string
+		delete  string
+		advance time.Duration
+	}
+
+	tests := map[string]struct {
+		maxEntries int
+		operations []operation
+		expected   map[string]bool
+	}{
+		"stores and returns values": {
+			maxEntries: 2,
+			operations: []operation{{set: "a"}, {set: "b"}},
+			expected:   map[string]bool{"a": true, "b": true},
+		},
+		"evicts least recently used entry": {
+			maxEntries: 2,
+			operations: []operation{{set: "a"}, {set: "b"}, {get: "a"}, {set: "c"}},
+			expected:   map[string]bool{"a": true, "b": false, "c": true},
+		},
+		"expires entries after ttl": {
+			maxEntries: 2,
+			operations: []operation{{set: "a", ttl: time.Minute}, {set: "b"}, {advance: time.Minute}},
+			expected:   map[string]bool{"a": false, "b": true},
+		},
+		"keeps entries before ttl": {
+			maxEntries: 2,
+			operations: []operation{{set: "a", ttl: time.Minute}, {advance: 59 * time.Second}},
+			expected:   map[string]bool{"a": true},
+		},
+		"deletes entries": {
+			maxEntries: 2,
+			operations: []operation{{set: "a"}, {set: "b"}, {delete: "a"}},
+			expected:   map[string]bool{"a": false, "b": true},
+		},
+		"unlimited entries": {
+			maxEntries: 0,
+			operations: []operation{{set: "a"}, {set: "b"}, {set: "c"}},
+			expected:   map[string]bool{"a": true, "b": true, "c": true},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			now := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+			cache := NewLRUCache(tt.maxEntries)
+			cache.now = func() time.Time { return now }
+
+			for _, op := range tt.operations {
+				switch {
+				case op.set != "":
+					cache.Set(op.set, []byte(op.set), op.ttl)
+				case op.get != "":
+					cache.Get(op.get)
+				case op.delete != "":
+					cache.Delete(op.delete)
+				}
+				now = now.Add(op.advance)
+			}
+
+			for key, expected := range tt.expected {
+				value, ok := cache.Get(key)
+				assert.Equal(t, expected, ok, key)
+				if expected {
+					assert.Equal(t, []byte(key), value)
+				}
+			}
+		})
+	}
+}
+
+func TestW3w_Cache(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		var body string
+		switch r.URL.Path {
+		case "/convert-to-coordinates", "/convert-to-3wa":
+			body = `{
+				"country": "GB",
+				"square": {
+					"southwest": {"lng": -0.195543, "lat": 51.520833},
+					"northeast": {"lng": -0.195499, "lat": 51.52086}
+				},
+				"nearestPlace": "Bayswater, London",
+				"coordinates": {"lng": -0.195521, "lat": 51.520847},
+				"words": "filled.count.soap",
+				"language": "en",
+				"map": "https://w3w.co/filled.count.soap"
+			}`
+		case "/available-languages":
+			body = `{"languages": [{"code": "en", "name": "English", "nativeName": "English"}]}`
+		}
+
+		_, err := rw.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	w := NewClient("example-api-key", WithEndpoint(u), WithCache(NewLRUCache(100)))
+
+	first, err := w.ConvertToCoordinates(ctx, "filled.count.soap")
+	assert.NoError(t, err)
+
+	second, err := w.ConvertToCoordinates(ctx, "///Filled.Count.Soap")
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// Any point inside the square is served from the cache populated by ConvertToCoordinates.
+	inside, err := w.ConvertTo3wa(ctx, &Coordinates{Lat: 51.520850, Lng: -0.195510})
+	assert.NoError(t, err)
+	assert.Equal(t, "filled.count.soap", inside.Words)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// A point outside the square requires a request.
+	_, err = w.ConvertTo3wa(ctx, &Coordinates{Lat: 51.5209, Lng: -0.1955})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	for i := 0; i < 3; i++ {
+		_, err = w.AvailableLanguages(ctx)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	assert.Equal(t, CacheStats{Hits: 4, Misses: 3}, w.CacheStats())
+}
+
+func TestW3w_CacheLocale(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		words, locale := "filled.count.soap", ""
+		switch r.URL.Query().Get("locale") {
+		case "mn_la":
+			words, locale = "sankhuu.vidio.anchin", "mn_la"
+		case "mn_cy":
+			words, locale = "санхүү.видео.анчин", "mn_cy"
+		}
+
+		_, err := fmt.Fprintf(rw, `{
+			"square": {
+				"southwest": {"lng": -0.195543, "lat": 51.520833},
+				"northeast": {"lng": -0.195499, "lat": 51.52086}
+			},
+			"coordinates": {"lng": -0.195521, "lat": 51.520847},
+			"words": %q,
+			"language": %q,
+			"locale": %q
+		}`, words, r.URL.Query().Get("language"), locale)
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	coordinates := &Coordinates{Lat: 51.520847, Lng: -0.195521}
+	w := NewClient("example-api-key", WithEndpoint(u), WithLanguage("mn"), WithLocale("mn_la"), WithCache(NewLRUCache(100)))
+
+	latin, err := w.ConvertTo3wa(ctx, coordinates)
+	assert.NoError(t, err)
+	assert.Equal(t, "sankhuu.vidio.anchin", latin.Words)
+	assert.Equal(t, "mn_la", latin.Locale)
+
+	// Each locale is cached separately.
+	cyrillic, err := w.ConvertTo3wa(ctx, coordinates, WithCallLocale("mn_cy"))
+	assert.NoError(t, err)
+	assert.Equal(t, "санхүү.видео.анчин", cyrillic.Words)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	cached, err := w.ConvertTo3wa(ctx, coordinates)
+	assert.NoError(t, err)
+	assert.Equal(t, latin, cached)
+
+	cached, err = w.ConvertToCoordinates(ctx, "санхүү.видео.анчин", WithCallLocale("mn_cy"))
+	assert.NoError(t, err)
+	assert.Equal(t, cyrillic, cached)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	english, err := w.ConvertTo3wa(ctx, coordinates, WithCallLanguage("en"), WithCallLocale(""))
+	assert.NoError(t, err)
+	assert.Equal(t, "filled.count.soap", english.Words)
+	assert.Empty(t, english.Locale)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestW3w_CacheWordsLanguage(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		// The words given to convert-to-coordinates are German whatever the requested language.
+		words, language := "filled.count.soap", r.URL.Query().Get("language")
+		if r.URL.Path == "/convert-to-coordinates" || language == "de" {
+			words, language = "dösend.geprüft.fächer", "de"
+		}
+
+		_, err := fmt.Fprintf(rw, `{
+			"square": {
+				"southwest": {"lng": -0.195543, "lat": 51.520833},
+				"northeast": {"lng": -0.195499, "lat": 51.52086}
+			},
+			"coordinates": {"lng": -0.195521, "lat": 51.520847},
+			"words": %q,
+			"language": %q
+		}`, words, language)
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	coordinates := &Coordinates{Lat: 51.520847, Lng: -0.195521}
+	w := NewClient("example-api-key", WithEndpoint(u), WithLanguage("en"), WithCache(NewLRUCache(100)))
+
+	german, err := w.ConvertToCoordinates(ctx, "dösend.geprüft.fächer")
+	assert.NoError(t, err)
+	assert.Equal(t, "de", german.Language)
+
+	// The square is cached in the language of its words rather than the requested language.
+	english, err := w.ConvertTo3wa(ctx, coordinates)
+	assert.NoError(t, err)
+	assert.Equal(t, "filled.count.soap", english.Words)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	cached, err := w.ConvertTo3wa(ctx, coordinates, WithCallLanguage("de"))
+	assert.NoError(t, err)
+	assert.Equal(t, german, cached)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,154 @@
+package what3words
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CallOption is an optional parameter for a single request, which takes precedence over the client defaults set in
+// NewClient.
+type CallOption func(*call)
+
+// Format is the format of an API response.
+type Format string
+
+// Response formats supported by ConvertTo3wa, ConvertToCoordinates and GridSection.
+const (
+	FormatJSON    Format = "json"
+	FormatGeoJSON Format = "geojson"
+)
+
+// call contains the settings for a single request.
+type call struct {
+	language  string
+	locale    string
+	header    http.Header
+	timeout   time.Duration
+	servedKey *string
+	format    Format
+	body      *[]byte
+}
+
+// WithCallLanguage is a CallOption for requesting 3 word addresses in a language other than the client language.
+// For AutoSuggest the language of the AutoSuggestInput takes precedence.
+func WithCallLanguage(language string) CallOption {
+	return func(c *call) {
+		c.language = language
+	}
+}
+
+// WithCallLocale is a CallOption for requesting 3 word addresses in a locale, for languages with multiple scripts,
+// e.g. mn_la or mn_cy, in place of the client locale. For AutoSuggest the locale of the AutoSuggestInput takes
+// precedence.
+func WithCallLocale(locale string) CallOption {
+	return func(c *call) {
+		c.locale = locale
+	}
+}
+
+// WithCallHeader is a CallOption for adding a header to the request, for example a tracing header.
+// Headers set this way replace the headers the client sets itself.
+func WithCallHeader(key, value string) CallOption {
+	return func(c *call) {
+		if c.header == nil {
+			c.header = make(http.Header)
+		}
+		c.header.Add(key, value)
+	}
+}
+
+// WithCallTimeout is a CallOption for limiting the time taken by the request, including retries and waiting for the
+// rate limiter. For batch conversions the timeout applies to each conversion.
+func WithCallTimeout(timeout time.Duration) CallOption {
+	return func(c *call) {
+		c.timeout = timeout
+	}
+}
+
+// WithServedKey is a CallOption for finding out which API key served the response. The name of the key is stored in
+// name once the request completes, or the empty string if the response came from the cache. Keys configured with
+// WithAPIKeys are identified by APIKey.Name, and the key passed to NewClient by its last 4 characters.
+func WithServedKey(name *string) CallOption {
+	return func(c *call) {
+		c.servedKey = name
+	}
+}
+
+// WithCallFormat is a CallOption for requesting the response of ConvertTo3wa, ConvertToCoordinates or GridSection
+// in the given format. The method still returns its usual type, decoded from the response, and if body is not nil the
+// raw response body is stored in it, e.g. to pass the API's GeoJSON straight to a map. Other methods only support
+// JSON and ignore the format, but still store the body. Requests with a body are never served from the cache.
+func WithCallFormat(format Format, body *[]byte) CallOption {
+	return func(c *call) {
+		c.format = format
+		c.body = body
+	}
+}
+
+// newCall applies the CallOptions to the client defaults.
+func (w *w3w) newCall(opts []CallOption) *call {
+	c := &call{language: w.language, locale: w.locale}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.servedKey != nil {
+		*c.servedKey = ""
+	}
+	return c
+}
+
+// context returns the context for the request, applying the call timeout if one is set.
+func (c *call) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout > 0 {
+		return context.WithTimeout(ctx, c.timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// cacheLanguage returns the language responses of the call are cached by, which is the locale if one is set,
+// as the 3 word addresses of a locale differ from those of its language.
+func (c *call) cacheLanguage() string {
+	if c.locale != "" {
+		return c.locale
+	}
+	return c.language
+}
+
+// cached reports whether the response of the call may be served from the cache, which is not the case if the raw
+// response body has been requested.
+func (c *call) cached() bool {
+	return c.body == nil
+}
+
+// setFormat sets the format parameter of a request to an endpoint supporting several formats.
+func (c *call) setFormat(query url.Values) {
+	if c.format != "" {
+		query.Set("format", string(c.format))
+	}
+}
+
+// setLanguage sets the language and locale parameters of a request.
+func (c *call) setLanguage(query url.Values) {
+	query.Set("language", c.language)
+	if c.locale != "" {
+		query.Set("locale", c.locale)
+	}
+}
+
+// headerKey returns the call headers in a canonical form, so requests with different headers are not deduplicated.
+func (c *call) headerKey() string {
+	if len(c.header) == 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, len(c.header))
+	for key, values := range c.header {
+		lines = append(lines, key+": "+strings.Join(values, ","))
+	}
+	sort.Strings(lines)
+	return "\n" + strings.Join(lines, "\n")
+}
@@ -0,0 +1,287 @@
+package what3words
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// callRequest is a request received by the server from newCallOptionsServer.
+type callRequest struct {
+	path      string
+	query     url.Values
+	requestID string
+}
+
+// newCallOptionsServer serves a square in the requested language from every conversion endpoint, empty suggestions
+// and grid sections, and records the requests it receives. Requests for slow.slow.slow take 200ms.
+func newCallOptionsServer(t *testing.T) (*httptest.Server, func() []callRequest) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		requests []callRequest
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, callRequest{path: r.URL.Path, query: r.URL.Query(), requestID: r.Header.Get("X-Request-Id")})
+		mu.Unlock()
+		if r.URL.Query().Get("words") == "slow.slow.slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+
+		switch r.URL.Path {
+		case "/autosuggest":
+			_, _ = rw.Write([]byte(`{"suggestions": []}`))
+		case "/grid-section":
+			_, _ = rw.Write([]byte(`{"lines": []}`))
+		default:
+			_, _ = fmt.Fprintf(rw, `{
+				"words": "filled.count.soap",
+				"language": %q,
+				"square": {"southwest": {"lat": 51.520833, "lng": -0.195543}, "northeast": {"lat": 51.52086, "lng": -0.195499}}
+			}`, r.URL.Query().Get("language"))
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts, func() []callRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+}
+
+func TestW3w_CallOptions(t *testing.T) {
+	ctx := context.Background()
+	coordinates := &Coordinates{Lat: 51.520847, Lng: -0.195521}
+
+	tests := map[string]struct {
+		call              func(w What3Words) error
+		expectedPath      string
+		expectedLanguage  string
+		expectedLocale    string
+		expectedRequestID string
+		expectedErr       error
+	}{
+		"client language by default": {
+			call: func(w What3Words) error {
+				_, err := w.GridSection(ctx, NewBoundingBox(51.52, -0.196, 51.521, -0.195))
+				return err
+			},
+			expectedPath:     "/grid-section",
+			expectedLanguage: "en",
+		},
+		"language and header override": {
+			call: func(w What3Words) error {
+				_, err := w.ConvertTo3wa(ctx, coordinates, WithCallLanguage("cy"), WithCallHeader("X-Request-Id", "abc"))
+				return err
+			},
+			expectedPath:      "/convert-to-3wa",
+			expectedLanguage:  "cy",
+			expectedRequestID: "abc",
+		},
+		"language override of GeoJSON": {
+			call: func(w What3Words) error {
+				_, err := w.ConvertToCoordinatesGeoJSON(ctx, "filled.count.soap", WithCallLanguage("hi"))
+				return err
+			},
+			expectedPath:     "/convert-to-coordinates",
+			expectedLanguage: "hi",
+		},
+		"locale": {
+			call: func(w What3Words) error {
+				_, err := w.ConvertTo3wa(ctx, coordinates, WithCallLanguage("cy"), WithCallLocale("mn_la"))
+				return err
+			},
+			expectedPath:     "/convert-to-3wa",
+			expectedLanguage: "cy",
+			expectedLocale:   "mn_la",
+		},
+		"autosuggest input takes precedence": {
+			call: func(w What3Words) error {
+				_, err := w.AutoSuggest(ctx, &AutoSuggestInput{Words: "filled.count.so", Language: "de"}, WithCallLanguage("fr"), WithCallLocale("mn_cy"))
+				return err
+			},
+			expectedPath:     "/autosuggest",
+			expectedLanguage: "de",
+			expectedLocale:   "mn_cy",
+		},
+		"timeout": {
+			call: func(w What3Words) error {
+				_, err := w.ConvertToCoordinates(ctx, "slow.slow.slow", WithCallTimeout(20*time.Millisecond))
+				return err
+			},
+			expectedPath:     "/convert-to-coordinates",
+			expectedLanguage: "en",
+			expectedErr:      context.DeadlineExceeded,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts, requests := newCallOptionsServer(t)
+			endpoint, err := url.Parse(ts.URL)
+			assert.NoError(t, err)
+			w := NewClient("key", WithEndpoint(endpoint), WithLanguage("en"), WithCache(NewLRUCache(100)))
+
+			err = tt.call(w)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			got := requests()
+			if assert.Len(t, got, 1) {
+				assert.Equal(t, tt.expectedPath, got[0].path)
+				assert.Equal(t, tt.expectedLanguage, got[0].query.Get("language"))
+				assert.Equal(t, tt.expectedLocale, got[0].query.Get("locale"))
+				assert.Equal(t, tt.expectedRequestID, got[0].requestID)
+			}
+		})
+	}
+}
+
+func TestW3w_CallOptionsCache(t *testing.T) {
+	ts, requests := newCallOptionsServer(t)
+	endpoint, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+	w := NewClient("key", WithEndpoint(endpoint), WithLanguage("en"), WithCache(NewLRUCache(100)))
+	ctx := context.Background()
+	coordinates := &Coordinates{Lat: 51.520847, Lng: -0.195521}
+
+	// Responses are cached by the language of the call.
+	for _, language := range []string{"cy", "en", "cy"} {
+		resp, err := w.ConvertTo3wa(ctx, coordinates, WithCallLanguage(language))
+		assert.NoError(t, err)
+		assert.Equal(t, language, resp.Language)
+	}
+	assert.Len(t, requests(), 2)
+
+	var served string
+	_, err = w.ConvertTo3wa(ctx, coordinates, WithCallLanguage("en"), WithServedKey(&served))
+	assert.NoError(t, err)
+	assert.Len(t, requests(), 2)
+	assert.Empty(t, served, "cached responses are not served by a key")
+
+	// The server does not echo the locale, so the response is cached by the locale of the call rather than its own.
+	_, err = w.ConvertTo3wa(ctx, coordinates, WithCallLanguage("mn"), WithCallLocale("mn_la"))
+	assert.NoError(t, err)
+	_, err = w.ConvertTo3wa(ctx, coordinates, WithCallLanguage("mn"))
+	assert.NoError(t, err)
+	assert.Len(t, requests(), 4, "a locale response is not served for its language")
+	_, err = w.ConvertTo3wa(ctx, coordinates, WithCallLanguage("mn"), WithCallLocale("mn_la"))
+	assert.NoError(t, err)
+	assert.Len(t, requests(), 4)
+}
+
+func TestW3w_CallFormat(t *testing.T) {
+	ctx := context.Background()
+	square := Square{
+		Southwest: Coordinates{Lat: 51.520833, Lng: -0.195543},
+		Northeast: Coordinates{Lat: 51.52086, Lng: -0.195499},
+	}
+	location := &LocationResponse{
+		Coordinates:  Coordinates{Lat: 51.520847, Lng: -0.195521},
+		Country:      "GB",
+		Language:     "en",
+		Map:          "https://w3w.co/filled.count.soap",
+		NearestPlace: "Bayswater, London",
+		Square:       square,
+		Words:        "filled.count.soap",
+	}
+	locationGeoJSON := `{"features": [{
+		"bbox": [-0.195543, 51.520833, -0.195499, 51.52086],
+		"geometry": {"coordinates": [-0.195521, 51.520847], "type": "Point"},
+		"type": "Feature",
+		"properties": {"country": "GB", "nearestPlace": "Bayswater, London", "words": "filled.count.soap", "language": "en", "map": "https://w3w.co/filled.count.soap"}
+	}], "type": "FeatureCollection"}`
+	gridGeoJSON := `{"features": [{
+		"geometry": {"coordinates": [[[-0.195543, 51.520833], [-0.195499, 51.520833]]], "type": "MultiLineString"},
+		"type": "Feature",
+		"properties": {}
+	}], "type": "FeatureCollection"}`
+
+	tests := map[string]struct {
+		call           func(w What3Words, opts ...CallOption) (interface{}, error)
+		responseBody   string
+		expected       interface{}
+		expectedFormat string
+		expectedErr    string
+	}{
+		"convert to 3wa": {
+			call: func(w What3Words, opts ...CallOption) (interface{}, error) {
+				return w.ConvertTo3wa(ctx, &location.Coordinates, opts...)
+			},
+			responseBody:   locationGeoJSON,
+			expected:       location,
+			expectedFormat: "geojson",
+		},
+		"convert to coordinates": {
+			call: func(w What3Words, opts ...CallOption) (interface{}, error) {
+				return w.ConvertToCoordinates(ctx, "filled.count.soap", opts...)
+			},
+			responseBody:   locationGeoJSON,
+			expected:       location,
+			expectedFormat: "geojson",
+		},
+		"grid section": {
+			call: func(w What3Words, opts ...CallOption) (interface{}, error) {
+				return w.GridSection(ctx, NewBoundingBox(51.52, -0.196, 51.521, -0.195), opts...)
+			},
+			responseBody:   gridGeoJSON,
+			expected:       &GridSection{Lines: []GridLine{{Start: square.Southwest, End: Coordinates{Lat: 51.520833, Lng: -0.195499}}}},
+			expectedFormat: "geojson",
+		},
+		"format not supported by the endpoint": {
+			call: func(w What3Words, opts ...CallOption) (interface{}, error) {
+				return w.AvailableLanguages(ctx, opts...)
+			},
+			responseBody: `{"languages": [{"code": "en", "name": "English", "nativeName": "English"}]}`,
+			expected:     []Language{{Code: "en", Name: "English", NativeName: "English"}},
+		},
+		"unexpected geometry": {
+			call: func(w What3Words, opts ...CallOption) (interface{}, error) {
+				return w.ConvertToCoordinates(ctx, "filled.count.soap", opts...)
+			},
+			responseBody:   gridGeoJSON,
+			expectedFormat: "geojson",
+			expectedErr:    `converting w3w to coordinates: decoding GeoJSON response body into output: expected Point geometry, got "MultiLineString"`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var formats []string
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				formats = append(formats, r.URL.Query().Get("format"))
+				_, _ = rw.Write([]byte(tt.responseBody))
+			}))
+			defer ts.Close()
+
+			endpoint, err := url.Parse(ts.URL)
+			assert.NoError(t, err)
+			w := NewClient("", WithEndpoint(endpoint), WithCache(NewLRUCache(100)))
+
+			for i := 0; i < 2; i++ {
+				var body []byte
+				resp, err := tt.call(w, WithCallFormat(FormatGeoJSON, &body))
+				if tt.expectedErr != "" {
+					assert.EqualError(t, err, tt.expectedErr)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, resp)
+				assert.Equal(t, tt.responseBody, string(body))
+			}
+
+			assert.Equal(t, []string{tt.expectedFormat, tt.expectedFormat}, formats, "requests asking for the body skip the cache")
+		})
+	}
+}
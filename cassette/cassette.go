@@ -0,0 +1,278 @@
+// Package cassette records what3words API traffic to a file and replays it, so integration tests can run offline
+// against real responses.
+//
+// A Recorder is an http.RoundTripper which is passed to the client with what3words.WithHTTPClient. In ModeRecord it
+// forwards requests to the real API and appends every request and response to a JSON Lines cassette file, with the
+// X-Api-Key header scrubbed. In ModeReplay it serves the recorded responses without any network access:
+//
+//	rec, err := cassette.New("testdata/convert.jsonl", cassette.ModeReplay)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Close()
+//
+//	w := what3words.NewClient(os.Getenv("W3W_API_KEY"), what3words.WithHTTPClient(rec.Client()))
+package cassette
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+)
+
+// Mode selects whether a Recorder records or replays.
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette and fails any request which was not recorded.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends requests to the real API and records them to the cassette, replacing its contents.
+	ModeRecord
+)
+
+// ErrNoMatch is returned in replay mode when a request does not match any unused recorded request.
+var ErrNoMatch = errors.New("no recorded response for request")
+
+// _scrubbed replaces the values of sensitive headers in the cassette.
+const _scrubbed = "REDACTED"
+
+// scrubbedHeaders are never written to the cassette in clear.
+var scrubbedHeaders = []string{"X-Api-Key", "Authorization"}
+
+// Interaction is a recorded request and its response. A cassette file contains one Interaction per line.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Option is an optional function parameter for New.
+type Option func(*Recorder)
+
+// WithTransport sets the transport used to send requests in record mode. Defaults to http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// Recorder is an http.RoundTripper which records or replays a cassette. A Recorder is safe for concurrent use.
+type Recorder struct {
+	mode      Mode
+	path      string
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	file         *os.File
+	writer       *bufio.Writer
+	interactions []Interaction
+	used         []bool
+}
+
+// New creates a Recorder for the cassette file at path. In ModeRecord the file is created or truncated;
+// in ModeReplay it must already exist. The Recorder must be closed with Close when it is no longer needed.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		mode:      mode,
+		path:      path,
+		transport: http.DefaultTransport,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	switch mode {
+	case ModeRecord:
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("creating cassette: %w", err)
+		}
+		r.file = file
+		r.writer = bufio.NewWriter(file)
+	case ModeReplay:
+		interactions, err := load(path)
+		if err != nil {
+			return nil, err
+		}
+		r.interactions = interactions
+		r.used = make([]bool, len(interactions))
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %d", mode)
+	}
+
+	return r, nil
+}
+
+// load reads the interactions in a cassette file.
+func load(path string) ([]Interaction, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening cassette: %w", err)
+	}
+	defer file.Close()
+
+	var interactions []Interaction
+	decoder := json.NewDecoder(file)
+	for {
+		var interaction Interaction
+		if err := decoder.Decode(&interaction); errors.Is(err, io.EOF) {
+			return interactions, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("reading cassette %s: %w", path, err)
+		}
+		interactions = append(interactions, interaction)
+	}
+}
+
+// Client returns an HTTP client which uses the Recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip records or replays a request, depending on the mode of the Recorder.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeRecord {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+// Close writes any buffered interactions to the cassette file and closes it.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.writer.Flush()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	r.file = nil
+
+	if err != nil {
+		return fmt.Errorf("writing cassette: %w", err)
+	}
+	return nil
+}
+
+// record sends the request and appends it and its response to the cassette.
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading response to record: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	line, err := json.Marshal(Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: scrub(req.Header),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     scrub(resp.Header),
+			Body:       string(body),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding interaction: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil, errors.New("cassette is closed")
+	}
+	if _, err := r.writer.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("writing cassette: %w", err)
+	}
+
+	return resp, nil
+}
+
+// replay returns the response of the first unused recorded request which matches the request.
+// Requests match if they have the same method and URL, ignoring the order of query parameters.
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	key := matchKey(req.Method, req.URL)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.used[i] {
+			continue
+		}
+
+		u, err := url.Parse(interaction.Request.URL)
+		if err != nil || matchKey(interaction.Request.Method, u) != key {
+			continue
+		}
+
+		r.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader([]byte(interaction.Response.Body))),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoMatch, req.Method, req.URL.Redacted())
+}
+
+// matchKey returns a key which is equal for requests with the same method and URL, whatever the order of their
+// query parameters.
+func matchKey(method string, u *url.URL) string {
+	normalised := *u
+	normalised.RawQuery = u.Query().Encode()
+	normalised.Fragment = ""
+	return method + " " + normalised.String()
+}
+
+// scrub returns a copy of the header with sensitive values replaced.
+func scrub(header http.Header) http.Header {
+	scrubbed := header.Clone()
+	for _, name := range scrubbedHeaders {
+		if scrubbed.Get(name) != "" {
+			scrubbed.Set(name, _scrubbed)
+		}
+	}
+	return scrubbed
+}
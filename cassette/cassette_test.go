@@ -0,0 +1,130 @@
+package cassette
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+	"github.com/henrwal/w3w-go-wrapper/w3wtest"
+)
+
+var filledCountSoap = what3words.LocationResponse{
+	Coordinates:  what3words.Coordinates{Lat: 51.520847, Lng: -0.195521},
+	Country:      "GB",
+	Language:     "en",
+	Map:          "https://w3w.co/filled.count.soap",
+	NearestPlace: "Bayswater, London",
+	Words:        "filled.count.soap",
+}
+
+// recordCassette records a cassette of a successful and a failed conversion against a fake server.
+func recordCassette(t *testing.T) string {
+	fake := w3wtest.NewServer()
+	defer fake.Close()
+	fake.AddLocation(filledCountSoap)
+
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	rec, err := New(path, ModeRecord)
+	assert.NoError(t, err)
+
+	client := what3words.NewClient("secret-key", what3words.WithEndpoint(fake.URL()), what3words.WithHTTPClient(rec.Client()))
+
+	_, err = client.ConvertToCoordinates(context.Background(), "filled.count.soap")
+	assert.NoError(t, err)
+	_, err = client.ConvertToCoordinates(context.Background(), "index.home.raft")
+	assert.ErrorIs(t, err, what3words.ErrBadWords)
+
+	assert.NoError(t, rec.Close())
+	return path
+}
+
+// recordedEndpoint returns the endpoint of the fake server the cassette was recorded against.
+func recordedEndpoint(t *testing.T, path string) *url.URL {
+	interactions, err := load(path)
+	assert.NoError(t, err)
+
+	u, err := url.Parse(interactions[0].Request.URL)
+	assert.NoError(t, err)
+	return &url.URL{Scheme: u.Scheme, Host: u.Host}
+}
+
+func TestRecorder_Record(t *testing.T) {
+	path := recordCassette(t)
+
+	contents, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(contents), "secret-key")
+	assert.Contains(t, string(contents), `"X-Api-Key":["REDACTED"]`)
+
+	interactions, err := load(path)
+	assert.NoError(t, err)
+	if assert.Len(t, interactions, 2) {
+		assert.Equal(t, http.StatusOK, interactions[0].Response.StatusCode)
+		assert.Contains(t, interactions[0].Response.Body, `"words":"filled.count.soap"`)
+		assert.Equal(t, http.StatusBadRequest, interactions[1].Response.StatusCode)
+		assert.Contains(t, interactions[1].Response.Body, `"code":"BadWords"`)
+	}
+}
+
+func TestRecorder_Replay(t *testing.T) {
+	path := recordCassette(t)
+	endpoint := recordedEndpoint(t, path).String()
+
+	rec, err := New(path, ModeReplay)
+	assert.NoError(t, err)
+	defer rec.Close()
+
+	// The fake server has been closed, so every response must come from the cassette.
+	client := &http.Client{Transport: rec}
+
+	// Query parameters are sent in a different order from the recording.
+	resp, err := client.Get(endpoint + "/convert-to-coordinates?words=index.home.raft&language=en")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.NoError(t, resp.Body.Close())
+
+	resp, err = client.Get(endpoint + "/convert-to-coordinates?words=filled.count.soap&language=en")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.NoError(t, resp.Body.Close())
+
+	// Each recorded interaction is only replayed once.
+	_, err = client.Get(endpoint + "/convert-to-coordinates?words=filled.count.soap&language=en")
+	assert.ErrorIs(t, err, ErrNoMatch)
+}
+
+func TestRecorder_ReplayClient(t *testing.T) {
+	path := recordCassette(t)
+	endpoint := recordedEndpoint(t, path)
+
+	rec, err := New(path, ModeReplay)
+	assert.NoError(t, err)
+	defer rec.Close()
+
+	client := what3words.NewClient("another-key", what3words.WithEndpoint(endpoint), what3words.WithHTTPClient(rec.Client()))
+
+	got, err := client.ConvertToCoordinates(context.Background(), "filled.count.soap")
+	assert.NoError(t, err)
+	assert.Equal(t, &filledCountSoap, got)
+
+	_, err = client.ConvertToCoordinates(context.Background(), "index.home.raft")
+	assert.ErrorIs(t, err, what3words.ErrBadWords)
+
+	_, err = client.ConvertToCoordinates(context.Background(), "daring.lion.race")
+	assert.ErrorIs(t, err, ErrNoMatch)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "missing.jsonl"), ModeReplay)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = New(filepath.Join(t.TempDir(), "cassette.jsonl"), Mode(7))
+	assert.EqualError(t, err, "unknown cassette mode 7")
+}
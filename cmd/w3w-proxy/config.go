@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+// config is the contents of the config file.
+type config struct {
+	// APIKey is the what3words API key used for every upstream request. W3W_API_KEY takes precedence.
+	APIKey string `json:"apiKey"`
+
+	// Endpoint is the upstream what3words API endpoint. Defaults to https://api.what3words.com/v3.
+	Endpoint string `json:"endpoint"`
+
+	// CacheEntries is the maximum number of entries in the shared cache. Zero disables caching.
+	CacheEntries int `json:"cacheEntries"`
+
+	// RateLimit is the budget shared by every caller. Requests over budget wait for a token.
+	RateLimit *rateLimit `json:"rateLimit"`
+
+	// Callers are the services allowed to use the proxy.
+	Callers []caller `json:"callers"`
+}
+
+// rateLimit is the JSON form of what3words.Limit.
+type rateLimit struct {
+	PerSecond float64 `json:"perSecond"`
+	Burst     int     `json:"burst"`
+	PerDay    int     `json:"perDay"`
+}
+
+// caller is a service which authenticates with its own token instead of the what3words API key.
+type caller struct {
+	// Name identifies the caller in usage reports.
+	Name string `json:"name"`
+
+	// Token is sent by the caller in place of an API key, in the X-Api-Key header or the key query parameter.
+	Token string `json:"token"`
+
+	// Admin callers can see the usage of every caller. Other callers only see their own.
+	Admin bool `json:"admin"`
+}
+
+// loadConfig reads and validates the config file at path, taking the API key from getenv if it is set.
+func loadConfig(path string, getenv func(string) string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	var cfg config
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+
+	if key := getenv("W3W_API_KEY"); key != "" {
+		cfg.APIKey = key
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return &cfg, nil
+}
+
+// validate checks that the config can be used to run the proxy.
+func (c *config) validate() error {
+	if c.APIKey == "" {
+		return errors.New("no API key: set W3W_API_KEY or apiKey")
+	}
+
+	if c.Endpoint != "" {
+		endpoint, err := url.Parse(c.Endpoint)
+		if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+			return fmt.Errorf("endpoint %q must be an absolute URL", c.Endpoint)
+		}
+	}
+
+	if len(c.Callers) == 0 {
+		return errors.New("no callers")
+	}
+
+	tokens := make(map[string]bool, len(c.Callers))
+	for i, caller := range c.Callers {
+		switch {
+		case caller.Name == "":
+			return fmt.Errorf("caller %d has no name", i)
+		case caller.Token == "":
+			return fmt.Errorf("caller %q has no token", caller.Name)
+		case tokens[caller.Token]:
+			return fmt.Errorf("caller %q has the same token as another caller", caller.Name)
+		}
+		tokens[caller.Token] = true
+	}
+
+	return nil
+}
+
+// options returns the options for the upstream client.
+func (c *config) options() []what3words.Option {
+	opts := []what3words.Option{
+		what3words.WithRequestDeduplication(),
+		what3words.WithRetry(what3words.RetryPolicy{MaxRetries: 2}),
+	}
+
+	if c.Endpoint != "" {
+		endpoint, _ := url.Parse(c.Endpoint)
+		opts = append(opts, what3words.WithEndpoint(endpoint))
+	}
+	if c.CacheEntries > 0 {
+		opts = append(opts, what3words.WithCache(what3words.NewLRUCache(c.CacheEntries)))
+	}
+	if c.RateLimit != nil {
+		opts = append(opts, what3words.WithRateLimit(what3words.NewRateLimiter(what3words.RateLimits{
+			Default: what3words.Limit{PerSecond: c.RateLimit.PerSecond, Burst: c.RateLimit.Burst, PerDay: c.RateLimit.PerDay},
+		})))
+	}
+
+	return opts
+}
@@ -0,0 +1,113 @@
+// Command w3w-proxy is a caching reverse proxy for the what3words v3 API.
+//
+// Usage:
+//
+//	w3w-proxy --config proxy.json [--addr :8080]
+//
+// The proxy serves the same /v3/... paths as the what3words API, so clients only need their endpoint changed, and
+// forwards requests upstream with a single API key. Responses are shared between callers through one cache and
+// upstream requests through one rate limiter. Callers authenticate with their own token in place of an API key, in
+// the X-Api-Key header or the key query parameter.
+//
+// GET /usage reports the number of requests and errors of the calling service by endpoint, along with the hits and
+// misses of the shared cache. Callers marked as admin see the usage of every caller.
+//
+// The config file is JSON:
+//
+//	{
+//	  "apiKey": "<what3words API key, or set W3W_API_KEY>",
+//	  "endpoint": "https://api.what3words.com/v3",
+//	  "cacheEntries": 100000,
+//	  "rateLimit": {"perSecond": 10, "burst": 20, "perDay": 100000},
+//	  "callers": [
+//	    {"name": "billing", "token": "<token>"},
+//	    {"name": "ops", "token": "<token>", "admin": true}
+//	  ]
+//	}
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// _shutdownTimeout is how long requests in flight are given to finish when the proxy is stopped.
+const _shutdownTimeout = 10 * time.Second
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stderr, os.Getenv))
+}
+
+// run starts the proxy with the flags in args and serves until ctx is done. It returns the exit code.
+func run(ctx context.Context, args []string, stderr io.Writer, getenv func(string) string) int {
+	fs := flag.NewFlagSet("w3w-proxy", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "", "path of the JSON config file (required)")
+	addr := fs.String("addr", ":8080", "address to listen on")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if *configPath == "" {
+		fmt.Fprintln(stderr, "w3w-proxy: --config is required")
+		return 2
+	}
+
+	logger := log.New(stderr, "w3w-proxy: ", log.LstdFlags)
+
+	cfg, err := loadConfig(*configPath, getenv)
+	if err != nil {
+		logger.Print(err)
+		return 1
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		logger.Print(err)
+		return 1
+	}
+
+	if err := serve(ctx, listener, newProxy(cfg, logger)); err != nil {
+		logger.Print(err)
+		return 1
+	}
+	return 0
+}
+
+// serve serves handler on listener until ctx is done, then waits for requests in flight to finish.
+func serve(ctx context.Context, listener net.Listener, handler http.Handler) error {
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), _shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+// languagePattern matches the 2 letter language codes accepted by the API.
+var languagePattern = regexp.MustCompile(`^[a-zA-Z]{2}$`)
+
+// badRequest returns the error written for an invalid query parameter, in the form the what3words API uses.
+func badRequest(code what3words.ErrorCode, format string, args ...interface{}) *what3words.APIError {
+	return &what3words.APIError{StatusCode: http.StatusBadRequest, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// parseFloats parses n comma separated numbers, or any number of pairs if n is zero.
+func parseFloats(value string, n int) ([]float64, bool) {
+	parts := strings.Split(value, ",")
+	if (n > 0 && len(parts) != n) || (n == 0 && len(parts)%2 != 0) {
+		return nil, false
+	}
+
+	values := make([]float64, 0, len(parts))
+	for _, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, false
+		}
+		values = append(values, v)
+	}
+	return values, true
+}
+
+// language returns the language parameter, defaulting to English.
+func language(query url.Values) (string, error) {
+	language := query.Get("language")
+	if language == "" {
+		return "en", nil
+	}
+	if !languagePattern.MatchString(language) {
+		return "", badRequest(what3words.ErrBadLanguage, "language must be a 2 letter ISO 639-1 code")
+	}
+	return strings.ToLower(language), nil
+}
+
+// callOptions returns the CallOptions for the language and locale parameters.
+func callOptions(query url.Values) ([]what3words.CallOption, error) {
+	language, err := language(query)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []what3words.CallOption{what3words.WithCallLanguage(language)}
+	if locale := query.Get("locale"); locale != "" {
+		opts = append(opts, what3words.WithCallLocale(locale))
+	}
+	return opts, nil
+}
+
+// geoJSON reports whether the format parameter asks for GeoJSON.
+func geoJSON(query url.Values) (bool, error) {
+	switch query.Get("format") {
+	case "", "json":
+		return false, nil
+	case "geojson":
+		return true, nil
+	default:
+		return false, badRequest(what3words.ErrBadFormat, "format must be json or geojson")
+	}
+}
+
+// coordinates parses the coordinates parameter.
+func coordinates(query url.Values) (*what3words.Coordinates, error) {
+	value := query.Get("coordinates")
+	if value == "" {
+		return nil, badRequest(what3words.ErrMissingCoordinates, "coordinates must be specified")
+	}
+	values, ok := parseFloats(value, 2)
+	if !ok {
+		return nil, badRequest(what3words.ErrBadCoordinates, "coordinates must be lat,lng")
+	}
+	return &what3words.Coordinates{Lat: values[0], Lng: values[1]}, nil
+}
+
+// boundingBox parses the bounding-box parameter.
+func boundingBox(query url.Values) (*what3words.BoundingBox, error) {
+	value := query.Get("bounding-box")
+	if value == "" {
+		return nil, badRequest(what3words.ErrMissingBoundingBox, "bounding-box must be specified")
+	}
+	values, ok := parseFloats(value, 4)
+	if !ok {
+		return nil, badRequest(what3words.ErrBadBoundingBox, "bounding-box must be south,west,north,east")
+	}
+	return what3words.NewBoundingBox(values[0], values[1], values[2], values[3]), nil
+}
+
+// autoSuggestInput parses the parameters of the autosuggest endpoints.
+func autoSuggestInput(query url.Values, inputParam string) (*what3words.AutoSuggestInput, error) {
+	input := &what3words.AutoSuggestInput{
+		Words:     query.Get(inputParam),
+		Locale:    query.Get("locale"),
+		InputType: what3words.InputType(query.Get("input-type")),
+	}
+	if input.Words == "" {
+		return nil, badRequest(what3words.ErrMissingInput, "%s must be specified", inputParam)
+	}
+
+	if query.Get("language") != "" {
+		language, err := language(query)
+		if err != nil {
+			return nil, err
+		}
+		input.Language = language
+	}
+
+	var err error
+	if value := query.Get("n-results"); value != "" {
+		if input.NResults, err = strconv.Atoi(value); err != nil {
+			return nil, badRequest(what3words.ErrBadNResults, "n-results must be a number")
+		}
+	}
+	if value := query.Get("n-focus-results"); value != "" {
+		if input.NFocusResults, err = strconv.Atoi(value); err != nil {
+			return nil, badRequest(what3words.ErrBadNFocusResults, "n-focus-results must be a number")
+		}
+	}
+	if value := query.Get("focus"); value != "" {
+		values, ok := parseFloats(value, 2)
+		if !ok {
+			return nil, badRequest(what3words.ErrBadFocus, "focus must be lat,lng")
+		}
+		input.Focus = &what3words.Coordinates{Lat: values[0], Lng: values[1]}
+	}
+	if value := query.Get("clip-to-country"); value != "" {
+		input.ClipToCountry = strings.Split(value, ",")
+	}
+	if value := query.Get("clip-to-bounding-box"); value != "" {
+		values, ok := parseFloats(value, 4)
+		if !ok {
+			return nil, badRequest(what3words.ErrBadClipToBoundingBox, "clip-to-bounding-box must be south,west,north,east")
+		}
+		input.ClipToBoundingBox = what3words.NewBoundingBox(values[0], values[1], values[2], values[3])
+	}
+	if value := query.Get("clip-to-circle"); value != "" {
+		values, ok := parseFloats(value, 3)
+		if !ok {
+			return nil, badRequest(what3words.ErrBadClipToCircle, "clip-to-circle must be lat,lng,kilometres")
+		}
+		input.ClipToCircle = &what3words.CoordinateRadius{
+			Coordinates: what3words.Coordinates{Lat: values[0], Lng: values[1]},
+			Radius:      int(values[2]),
+		}
+	}
+	if value := query.Get("clip-to-polygon"); value != "" {
+		values, ok := parseFloats(value, 0)
+		if !ok {
+			return nil, badRequest(what3words.ErrBadClipToPolygon, "clip-to-polygon must be lat,lng pairs")
+		}
+		for i := 0; i < len(values); i += 2 {
+			input.ClipToPolygon = append(input.ClipToPolygon, what3words.Coordinates{Lat: values[i], Lng: values[i+1]})
+		}
+	}
+	if value := query.Get("prefer-land"); value != "" {
+		preferLand, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, badRequest(what3words.ErrBadPreferLand, "prefer-land must be true or false")
+		}
+		input.PreferLand = &preferLand
+	}
+
+	return input, nil
+}
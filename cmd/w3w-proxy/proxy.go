@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+// validationCodes maps the fields of client-side validation errors to the error codes the API would return.
+var validationCodes = map[string]what3words.ErrorCode{
+	"words":                what3words.ErrBadWords,
+	"coordinates":          what3words.ErrBadCoordinates,
+	"bounding-box":         what3words.ErrBadBoundingBox,
+	"input":                what3words.ErrBadInput,
+	"input-type":           what3words.ErrBadInputType,
+	"n-results":            what3words.ErrBadNResults,
+	"n-focus-results":      what3words.ErrBadNFocusResults,
+	"focus":                what3words.ErrBadFocus,
+	"clip-to-bounding-box": what3words.ErrBadClipToBoundingBox,
+	"clip-to-circle":       what3words.ErrBadClipToCircle,
+	"clip-to-polygon":      what3words.ErrBadClipToPolygon,
+}
+
+// Error codes returned in place of upstream key and quota errors, so callers do not mistake them for problems with
+// their own token.
+const (
+	errUpstreamKeyRejected   what3words.ErrorCode = "UpstreamKeyRejected"
+	errUpstreamQuotaExceeded what3words.ErrorCode = "UpstreamQuotaExceeded"
+)
+
+// endpoint handles a request to an API endpoint and returns the value to write as the JSON response,
+// or nil for an empty response.
+type endpoint func(ctx context.Context, query url.Values) (interface{}, error)
+
+// proxy serves the what3words v3 API to authenticated callers, forwarding requests upstream through a single client
+// with the real API key, cache and rate limiter.
+type proxy struct {
+	callers []caller
+	usage   *usage
+	log     *log.Logger
+	client  what3words.What3Words
+}
+
+// newProxy creates a proxy from the config, logging upstream failures to logger.
+func newProxy(cfg *config, logger *log.Logger) *proxy {
+	return &proxy{
+		callers: cfg.Callers,
+		usage:   newUsage(),
+		log:     logger,
+		client:  what3words.NewClient(cfg.APIKey, cfg.options()...),
+	}
+}
+
+// caller returns the caller with the token. Every configured token is compared in constant time, so the time taken
+// does not reveal how much of a token was guessed correctly.
+func (p *proxy) caller(token string) (caller, bool) {
+	var (
+		found caller
+		ok    bool
+	)
+	for _, c := range p.callers {
+		if subtle.ConstantTimeCompare([]byte(c.Token), []byte(token)) == 1 {
+			found, ok = c, true
+		}
+	}
+	return found, ok
+}
+
+// endpoints returns the handlers of the API endpoints by name.
+func (p *proxy) endpoints() map[string]endpoint {
+	return map[string]endpoint{
+		"convert-to-3wa":               p.convertTo3wa,
+		"convert-to-coordinates":       p.convertToCoordinates,
+		"autosuggest":                  p.autoSuggest,
+		"autosuggest-with-coordinates": p.autoSuggestWithCoordinates,
+		"autosuggest-selection":        p.reportSelection,
+		"grid-section":                 p.gridSection,
+		"available-languages":          p.availableLanguages,
+	}
+}
+
+// ServeHTTP authenticates the caller and serves an API endpoint or the usage report.
+func (p *proxy) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("X-Api-Key")
+	if token == "" {
+		token = r.URL.Query().Get("key")
+	}
+	if token == "" {
+		writeError(rw, &what3words.APIError{
+			StatusCode: http.StatusUnauthorized,
+			Code:       what3words.ErrMissingKey,
+			Message:    "Authentication failed; missing API key",
+		})
+		return
+	}
+
+	caller, ok := p.caller(token)
+	if !ok {
+		writeError(rw, &what3words.APIError{
+			StatusCode: http.StatusUnauthorized,
+			Code:       what3words.ErrInvalidKey,
+			Message:    "Authentication failed; invalid API key",
+		})
+		return
+	}
+
+	if r.URL.Path == "/usage" {
+		writeJSON(rw, p.usage.report(caller, p.client.CacheStats()))
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/v3/")
+	handler, ok := p.endpoints()[name]
+	if !ok || name == r.URL.Path {
+		writeError(rw, &what3words.APIError{StatusCode: http.StatusNotFound, Code: "NotFound", Message: "Unknown endpoint " + r.URL.Path})
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(rw, &what3words.APIError{StatusCode: http.StatusMethodNotAllowed, Code: "MethodNotAllowed", Message: "Only GET is supported"})
+		return
+	}
+
+	value, err := handler(r.Context(), r.URL.Query())
+	p.usage.record(caller.Name, name, err)
+	if err != nil {
+		p.writeError(rw, caller, name, err)
+		return
+	}
+
+	if value == nil {
+		rw.WriteHeader(http.StatusOK)
+		return
+	}
+	writeJSON(rw, value)
+}
+
+// writeError writes an error in the form returned by the what3words API. Errors returned by the API and invalid
+// requests are passed on to the caller. The API rejecting the proxy's own key, or its quota being used up, is logged
+// and reported as a bad gateway or service unavailable, and other failures are logged and reported as a bad gateway.
+func (p *proxy) writeError(rw http.ResponseWriter, c caller, endpoint string, err error) {
+	var (
+		apiErr        *what3words.APIError
+		validationErr *what3words.ValidationError
+	)
+
+	switch {
+	case errors.Is(err, what3words.ErrMissingKey) || errors.Is(err, what3words.ErrInvalidKey) ||
+		errors.Is(err, what3words.ErrSuspendedKey):
+		p.log.Printf("%s %s: %v", c.Name, endpoint, err)
+		writeError(rw, &what3words.APIError{
+			StatusCode: http.StatusBadGateway,
+			Code:       errUpstreamKeyRejected,
+			Message:    "The proxy's upstream API key was rejected",
+		})
+	case errors.Is(err, what3words.ErrQuotaExceeded):
+		p.log.Printf("%s %s: %v", c.Name, endpoint, err)
+		upstreamErr := &what3words.APIError{
+			StatusCode: http.StatusServiceUnavailable,
+			Code:       errUpstreamQuotaExceeded,
+			Message:    "The proxy's upstream API quota is exceeded",
+		}
+		if errors.As(err, &apiErr) {
+			upstreamErr.RetryAfter = apiErr.RetryAfter
+		}
+		writeError(rw, upstreamErr)
+	case errors.As(err, &apiErr):
+		writeError(rw, apiErr)
+	case errors.As(err, &validationErr):
+		code, ok := validationCodes[validationErr.Field]
+		if !ok {
+			code = what3words.ErrBadInput
+		}
+		writeError(rw, badRequest(code, "%s", validationErr.Message))
+	default:
+		p.log.Printf("%s %s: %v", c.Name, endpoint, err)
+		writeError(rw, &what3words.APIError{
+			StatusCode: http.StatusBadGateway,
+			Code:       what3words.ErrInternalServerError,
+			Message:    "Upstream request failed",
+		})
+	}
+}
+
+func writeError(rw http.ResponseWriter, err *what3words.APIError) {
+	if err.RetryAfter > 0 {
+		rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(err.RetryAfter.Seconds()))))
+	}
+
+	code := err.Code
+	if code == "" {
+		code = what3words.ErrInternalServerError
+	}
+
+	body := map[string]interface{}{
+		"error": map[string]string{"code": string(code), "message": err.Message},
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(err.StatusCode)
+	_ = json.NewEncoder(rw).Encode(body)
+}
+
+func writeJSON(rw http.ResponseWriter, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(v)
+}
+
+// upstreamGeoJSON makes a request in the geojson format and returns the upstream response body as is, so callers get
+// the same GeoJSON as from the API rather than the GeoJSON variants of the client.
+func upstreamGeoJSON(opts []what3words.CallOption, request func(opts ...what3words.CallOption) error) (interface{}, error) {
+	var body []byte
+	if err := request(append(opts, what3words.WithCallFormat(what3words.FormatGeoJSON, &body))...); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(body), nil
+}
+
+func (p *proxy) convertTo3wa(ctx context.Context, query url.Values) (interface{}, error) {
+	c, err := coordinates(query)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := callOptions(query)
+	if err != nil {
+		return nil, err
+	}
+	geoJSON, err := geoJSON(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if geoJSON {
+		return upstreamGeoJSON(opts, func(opts ...what3words.CallOption) error {
+			_, err := p.client.ConvertTo3wa(ctx, c, opts...)
+			return err
+		})
+	}
+	return p.client.ConvertTo3wa(ctx, c, opts...)
+}
+
+func (p *proxy) convertToCoordinates(ctx context.Context, query url.Values) (interface{}, error) {
+	words := query.Get("words")
+	if words == "" {
+		return nil, badRequest(what3words.ErrMissingWords, "words must be specified")
+	}
+	opts, err := callOptions(query)
+	if err != nil {
+		return nil, err
+	}
+	geoJSON, err := geoJSON(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if geoJSON {
+		return upstreamGeoJSON(opts, func(opts ...what3words.CallOption) error {
+			_, err := p.client.ConvertToCoordinates(ctx, words, opts...)
+			return err
+		})
+	}
+	return p.client.ConvertToCoordinates(ctx, words, opts...)
+}
+
+func (p *proxy) autoSuggest(ctx context.Context, query url.Values) (interface{}, error) {
+	input, err := autoSuggestInput(query, "input")
+	if err != nil {
+		return nil, err
+	}
+	return p.client.AutoSuggest(ctx, input)
+}
+
+func (p *proxy) autoSuggestWithCoordinates(ctx context.Context, query url.Values) (interface{}, error) {
+	input, err := autoSuggestInput(query, "input")
+	if err != nil {
+		return nil, err
+	}
+	return p.client.AutoSuggestWithCoordinates(ctx, input)
+}
+
+func (p *proxy) reportSelection(ctx context.Context, query url.Values) (interface{}, error) {
+	input, err := autoSuggestInput(query, "raw-input")
+	if err != nil {
+		return nil, err
+	}
+
+	selection := query.Get("selection")
+	if selection == "" {
+		return nil, badRequest(what3words.ErrBadSelection, "selection must be specified")
+	}
+	rank, err := strconv.Atoi(query.Get("rank"))
+	if err != nil {
+		return nil, badRequest(what3words.ErrBadRank, "rank must be a number")
+	}
+
+	return nil, p.client.ReportSelection(ctx, input, what3words.Suggestion{Words: selection}, rank)
+}
+
+func (p *proxy) gridSection(ctx context.Context, query url.Values) (interface{}, error) {
+	box, err := boundingBox(query)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := callOptions(query)
+	if err != nil {
+		return nil, err
+	}
+	geoJSON, err := geoJSON(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if geoJSON {
+		return upstreamGeoJSON(opts, func(opts ...what3words.CallOption) error {
+			_, err := p.client.GridSection(ctx, box, opts...)
+			return err
+		})
+	}
+	return p.client.GridSection(ctx, box, opts...)
+}
+
+func (p *proxy) availableLanguages(ctx context.Context, _ url.Values) (interface{}, error) {
+	languages, err := p.client.AvailableLanguages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return what3words.AvailableLanguages{Languages: languages}, nil
+}
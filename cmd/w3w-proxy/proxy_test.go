@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+	"github.com/henrwal/w3w-go-wrapper/w3wtest"
+)
+
+var filledCountSoap = what3words.LocationResponse{
+	Coordinates:  what3words.Coordinates{Lat: 51.520847, Lng: -0.195521},
+	Country:      "GB",
+	Language:     "en",
+	Map:          "https://w3w.co/filled.count.soap",
+	NearestPlace: "Bayswater, London",
+	Square: what3words.Square{
+		Northeast: what3words.Coordinates{Lat: 51.52086, Lng: -0.195499},
+		Southwest: what3words.Coordinates{Lat: 51.520833, Lng: -0.195543},
+	},
+	Words: "filled.count.soap",
+}
+
+// newTestProxy starts a proxy in front of a fake what3words server with two callers: billing, using the token
+// billing-token, and ops, an admin using the token ops-token.
+func newTestProxy(t *testing.T) (*w3wtest.Server, *url.URL) {
+	fake := w3wtest.NewServer()
+	t.Cleanup(fake.Close)
+	fake.AddLocation(filledCountSoap)
+
+	p := newProxy(&config{
+		APIKey:       "upstream-key",
+		Endpoint:     fake.URL().String(),
+		CacheEntries: 100,
+		Callers: []caller{
+			{Name: "billing", Token: "billing-token"},
+			{Name: "ops", Token: "ops-token", Admin: true},
+		},
+	}, log.New(io.Discard, "", 0))
+
+	server := httptest.NewServer(p)
+	t.Cleanup(server.Close)
+
+	u, _ := url.Parse(server.URL)
+	return fake, u
+}
+
+func newProxyClient(proxyURL *url.URL, token string) what3words.What3Words {
+	return what3words.NewClient(token, what3words.WithEndpoint(proxyURL.JoinPath("/v3")))
+}
+
+func upstreamRequests(fake *w3wtest.Server, endpoint string) []w3wtest.Request {
+	var requests []w3wtest.Request
+	for _, r := range fake.Requests() {
+		if r.Endpoint == endpoint {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+func TestProxy(t *testing.T) {
+	fake, proxyURL := newTestProxy(t)
+	ctx := context.Background()
+	billing := newProxyClient(proxyURL, "billing-token")
+	ops := newProxyClient(proxyURL, "ops-token")
+
+	resp, err := billing.ConvertTo3wa(ctx, &filledCountSoap.Coordinates)
+	assert.NoError(t, err)
+	assert.Equal(t, &filledCountSoap, resp)
+
+	// The second caller is served from the shared cache, by coordinates and by words.
+	resp, err = ops.ConvertTo3wa(ctx, &filledCountSoap.Coordinates)
+	assert.NoError(t, err)
+	assert.Equal(t, &filledCountSoap, resp)
+	resp, err = ops.ConvertToCoordinates(ctx, "filled.count.soap")
+	assert.NoError(t, err)
+	assert.Equal(t, &filledCountSoap, resp)
+
+	if requests := upstreamRequests(fake, w3wtest.EndpointConvertTo3wa); assert.Len(t, requests, 1) {
+		assert.Equal(t, "upstream-key", requests[0].Header.Get("X-Api-Key"))
+	}
+	assert.Empty(t, upstreamRequests(fake, w3wtest.EndpointConvertToCoordinates))
+
+	suggestions, err := billing.AutoSuggest(ctx, &what3words.AutoSuggestInput{
+		Words:         "filled.count.so",
+		ClipToCountry: []string{"GB"},
+		Focus:         &what3words.Coordinates{Lat: 51.5, Lng: -0.2},
+		NResults:      2,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, suggestions.Suggestions, 1) {
+		assert.Equal(t, "filled.count.soap", suggestions.Suggestions[0].Words)
+	}
+	if requests := upstreamRequests(fake, w3wtest.EndpointAutoSuggest); assert.Len(t, requests, 1) {
+		assert.Equal(t, "GB", requests[0].Query.Get("clip-to-country"))
+		assert.Equal(t, "51.500000,-0.200000", requests[0].Query.Get("focus"))
+		assert.Equal(t, "2", requests[0].Query.Get("n-results"))
+	}
+
+	err = billing.ReportSelection(ctx, &what3words.AutoSuggestInput{Words: "filled.count.so"}, suggestions.Suggestions[0], 1)
+	assert.NoError(t, err)
+	if requests := upstreamRequests(fake, w3wtest.EndpointAutoSuggestSelection); assert.Len(t, requests, 1) {
+		assert.Equal(t, "filled.count.so", requests[0].Query.Get("raw-input"))
+		assert.Equal(t, "filled.count.soap", requests[0].Query.Get("selection"))
+	}
+
+	languages, err := billing.AvailableLanguages(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []what3words.Language{{Code: "en", Name: "English", NativeName: "English"}}, languages)
+}
+
+func TestProxy_GeoJSON(t *testing.T) {
+	fake, proxyURL := newTestProxy(t)
+	ctx := context.Background()
+	billing := newProxyClient(proxyURL, "billing-token")
+
+	box := what3words.BoundingBox{SouthLat: 51.520833, WestLng: -0.195543, NorthLat: 51.52086, EastLng: -0.195499}
+	grid := what3words.GridSection{Lines: []what3words.GridLine{{Start: filledCountSoap.Square.Southwest, End: filledCountSoap.Square.Northeast}}}
+	fake.AddGridSection(box, grid)
+
+	// The API's geojson format is passed through, so the client decodes it as it would from the API.
+	var body []byte
+	resp, err := billing.ConvertToCoordinates(ctx, "filled.count.soap", what3words.WithCallFormat(what3words.FormatGeoJSON, &body))
+	assert.NoError(t, err)
+	assert.Equal(t, &filledCountSoap, resp)
+
+	var collection struct {
+		Features []struct {
+			BBox     []float64 `json:"bbox"`
+			Geometry struct {
+				Type string `json:"type"`
+			} `json:"geometry"`
+		} `json:"features"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &collection))
+	if assert.Len(t, collection.Features, 1) {
+		assert.Equal(t, "Point", collection.Features[0].Geometry.Type)
+		assert.Equal(t, []float64{-0.195543, 51.520833, -0.195499, 51.52086}, collection.Features[0].BBox)
+	}
+
+	resp, err = billing.ConvertTo3wa(ctx, &filledCountSoap.Coordinates, what3words.WithCallFormat(what3words.FormatGeoJSON, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, &filledCountSoap, resp)
+
+	gotGrid, err := billing.GridSection(ctx, &box, what3words.WithCallFormat(what3words.FormatGeoJSON, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, &grid, gotGrid)
+
+	for _, endpoint := range []string{w3wtest.EndpointConvertTo3wa, w3wtest.EndpointConvertToCoordinates, w3wtest.EndpointGridSection} {
+		if requests := upstreamRequests(fake, endpoint); assert.Len(t, requests, 1) {
+			assert.Equal(t, "geojson", requests[0].Query.Get("format"))
+		}
+	}
+}
+
+func TestProxy_Errors(t *testing.T) {
+	fake, proxyURL := newTestProxy(t)
+	fake.SetError(w3wtest.EndpointGridSection, http.StatusBadRequest, what3words.ErrBadBoundingBoxTooBig, "The bounding box is too big")
+	fake.SetError(w3wtest.EndpointAvailableLanguages, http.StatusUnauthorized, what3words.ErrInvalidKey, "Authentication failed; invalid API key")
+	fake.SetError(w3wtest.EndpointAutoSuggest, http.StatusPaymentRequired, what3words.ErrQuotaExceeded, "Quota Exceeded")
+
+	tests := map[string]struct {
+		path           string
+		query          url.Values
+		token          string
+		expectedStatus int
+		expectedCode   what3words.ErrorCode
+	}{
+		"missing token": {
+			path:           "/v3/convert-to-coordinates",
+			query:          url.Values{"words": {"filled.count.soap"}},
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   what3words.ErrMissingKey,
+		},
+		"unknown token": {
+			path:           "/v3/convert-to-coordinates",
+			query:          url.Values{"words": {"filled.count.soap"}},
+			token:          "upstream-key",
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   what3words.ErrInvalidKey,
+		},
+		"upstream error": {
+			path:           "/v3/grid-section",
+			query:          url.Values{"bounding-box": {"51,-1,52,0"}},
+			token:          "billing-token",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   what3words.ErrBadBoundingBoxTooBig,
+		},
+		"upstream key rejected": {
+			path:           "/v3/available-languages",
+			token:          "billing-token",
+			expectedStatus: http.StatusBadGateway,
+			expectedCode:   errUpstreamKeyRejected,
+		},
+		"upstream quota exceeded": {
+			path:           "/v3/autosuggest",
+			query:          url.Values{"input": {"filled.count.so"}},
+			token:          "billing-token",
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCode:   errUpstreamQuotaExceeded,
+		},
+		"client validation error": {
+			path:           "/v3/convert-to-coordinates",
+			query:          url.Values{"words": {"filled.count"}},
+			token:          "billing-token",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   what3words.ErrBadWords,
+		},
+		"client validation error of coordinates": {
+			path:           "/v3/convert-to-3wa",
+			query:          url.Values{"coordinates": {"151.520847,-0.195521"}},
+			token:          "billing-token",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   what3words.ErrBadCoordinates,
+		},
+		"client validation error of bounding box": {
+			path:           "/v3/grid-section",
+			query:          url.Values{"bounding-box": {"52,-1,51,0"}},
+			token:          "billing-token",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   what3words.ErrBadBoundingBox,
+		},
+		"invalid parameter": {
+			path:           "/v3/convert-to-3wa",
+			query:          url.Values{"coordinates": {"north,south"}},
+			token:          "billing-token",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   what3words.ErrBadCoordinates,
+		},
+		"missing parameter": {
+			path:           "/v3/autosuggest",
+			token:          "billing-token",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   what3words.ErrMissingInput,
+		},
+		"unknown endpoint": {
+			path:           "/v3/convert",
+			token:          "billing-token",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "NotFound",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := proxyURL.JoinPath(tt.path)
+			u.RawQuery = tt.query.Encode()
+			req, _ := http.NewRequest(http.MethodGet, u.String(), nil)
+			if tt.token != "" {
+				req.Header.Set("X-Api-Key", tt.token)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+
+			var body struct {
+				Error struct {
+					Code string `json:"code"`
+				} `json:"error"`
+			}
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, string(tt.expectedCode), body.Error.Code)
+		})
+	}
+}
+
+func TestProxy_Usage(t *testing.T) {
+	_, proxyURL := newTestProxy(t)
+	ctx := context.Background()
+	billing := newProxyClient(proxyURL, "billing-token")
+	ops := newProxyClient(proxyURL, "ops-token")
+
+	_, err := billing.ConvertToCoordinates(ctx, "filled.count.soap")
+	assert.NoError(t, err)
+	_, err = billing.ConvertToCoordinates(ctx, "filled.count.soap")
+	assert.NoError(t, err)
+	_, err = billing.ConvertToCoordinates(ctx, "index.home.raft")
+	assert.Error(t, err)
+	_, err = ops.AvailableLanguages(ctx)
+	assert.NoError(t, err)
+
+	getUsage := func(token string) usageReport {
+		req, _ := http.NewRequest(http.MethodGet, proxyURL.JoinPath("/usage").String(), nil)
+		req.Header.Set("X-Api-Key", token)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		var report usageReport
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		return report
+	}
+
+	billingUsage := callerUsage{Requests: 3, Errors: 1, Endpoints: map[string]uint64{"convert-to-coordinates": 3}}
+	opsUsage := callerUsage{Requests: 1, Endpoints: map[string]uint64{"available-languages": 1}}
+
+	report := getUsage("billing-token")
+	assert.Equal(t, map[string]callerUsage{"billing": billingUsage}, report.Callers)
+	assert.Equal(t, cacheUsage{Hits: 1, Misses: 3}, report.Cache)
+
+	report = getUsage("ops-token")
+	assert.Equal(t, map[string]callerUsage{"billing": billingUsage, "ops": opsUsage}, report.Callers)
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := map[string]struct {
+		config      string
+		env         map[string]string
+		expectedKey string
+		expectedErr string
+	}{
+		"valid": {
+			config:      `{"apiKey": "key", "callers": [{"name": "billing", "token": "t1"}]}`,
+			expectedKey: "key",
+		},
+		"key from environment": {
+			config:      `{"callers": [{"name": "billing", "token": "t1"}]}`,
+			env:         map[string]string{"W3W_API_KEY": "env-key"},
+			expectedKey: "env-key",
+		},
+		"missing key": {
+			config:      `{"callers": [{"name": "billing", "token": "t1"}]}`,
+			expectedErr: "no API key: set W3W_API_KEY or apiKey",
+		},
+		"no callers": {
+			config:      `{"apiKey": "key"}`,
+			expectedErr: "no callers",
+		},
+		"duplicate token": {
+			config:      `{"apiKey": "key", "callers": [{"name": "billing", "token": "t1"}, {"name": "ops", "token": "t1"}]}`,
+			expectedErr: "caller \"ops\" has the same token as another caller",
+		},
+		"relative endpoint": {
+			config:      `{"apiKey": "key", "endpoint": "/v3", "callers": [{"name": "billing", "token": "t1"}]}`,
+			expectedErr: "endpoint \"/v3\" must be an absolute URL",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "proxy.json")
+			assert.NoError(t, os.WriteFile(path, []byte(tt.config), 0o600))
+
+			cfg, err := loadConfig(path, func(key string) string { return tt.env[key] })
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, "invalid config "+path+": "+tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedKey, cfg.APIKey)
+		})
+	}
+}
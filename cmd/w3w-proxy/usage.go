@@ -0,0 +1,93 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+// usage counts the requests made by each caller.
+type usage struct {
+	since time.Time
+
+	mu      sync.Mutex
+	callers map[string]*callerUsage
+}
+
+// callerUsage is the number of requests made by a caller.
+type callerUsage struct {
+	// Requests is the number of requests to API endpoints, including failed requests.
+	Requests uint64 `json:"requests"`
+
+	// Errors is the number of requests which failed.
+	Errors uint64 `json:"errors"`
+
+	// Endpoints contains the number of requests by endpoint name.
+	Endpoints map[string]uint64 `json:"endpoints"`
+}
+
+// usageReport is the response of the usage endpoint.
+type usageReport struct {
+	// Since is when the proxy started counting.
+	Since time.Time `json:"since"`
+
+	// Callers contains the usage of each caller by name.
+	Callers map[string]callerUsage `json:"callers"`
+
+	// Cache contains the hits and misses of the shared cache.
+	Cache cacheUsage `json:"cache"`
+}
+
+// cacheUsage is the JSON form of what3words.CacheStats.
+type cacheUsage struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+func newUsage() *usage {
+	return &usage{since: time.Now(), callers: make(map[string]*callerUsage)}
+}
+
+// record counts a request by the caller to the endpoint.
+func (u *usage) record(caller, endpoint string, err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	c, ok := u.callers[caller]
+	if !ok {
+		c = &callerUsage{Endpoints: make(map[string]uint64)}
+		u.callers[caller] = c
+	}
+
+	c.Requests++
+	c.Endpoints[endpoint]++
+	if err != nil {
+		c.Errors++
+	}
+}
+
+// report returns the usage visible to the caller: every caller's for admins, otherwise only their own.
+func (u *usage) report(c caller, cache what3words.CacheStats) usageReport {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	report := usageReport{
+		Since:   u.since,
+		Callers: make(map[string]callerUsage),
+		Cache:   cacheUsage{Hits: cache.Hits, Misses: cache.Misses},
+	}
+	for name, usage := range u.callers {
+		if !c.Admin && name != c.Name {
+			continue
+		}
+
+		endpoints := make(map[string]uint64, len(usage.Endpoints))
+		for endpoint, n := range usage.Endpoints {
+			endpoints[endpoint] = n
+		}
+		report.Callers[name] = callerUsage{Requests: usage.Requests, Errors: usage.Errors, Endpoints: endpoints}
+	}
+
+	return report
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+// parseArgs parses the flags in args, which may appear before, after or between the positional arguments, and
+// returns the positional arguments. Arguments starting with a minus sign followed by a digit, such as negative
+// coordinates, are positional, and every argument after -- is positional.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var flags, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			positional = append(positional, args[i+1:]...)
+			i = len(args)
+		case len(arg) < 2 || arg[0] != '-' || (arg[1] >= '0' && arg[1] <= '9') || arg[1] == '.':
+			positional = append(positional, arg)
+		default:
+			flags = append(flags, arg)
+
+			name := strings.TrimLeft(arg, "-")
+			if strings.Contains(name, "=") {
+				continue
+			}
+			if f := fs.Lookup(name); f != nil && !isBoolFlag(f) && i+1 < len(args) {
+				i++
+				flags = append(flags, args[i])
+			}
+		}
+	}
+
+	if err := fs.Parse(flags); err != nil {
+		if err == flag.ErrHelp {
+			return nil, err
+		}
+		return nil, &usageError{err: err}
+	}
+
+	return positional, nil
+}
+
+// isBoolFlag reports whether the flag does not take a value.
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// parseFloats parses a comma separated list of numbers. If n is positive exactly n numbers are required.
+func parseFloats(name, value string, n int) ([]float64, error) {
+	parts := strings.Split(value, ",")
+	if n > 0 && len(parts) != n {
+		return nil, &usageError{err: fmt.Errorf("invalid %s %q: expected %d comma separated numbers", name, value, n)}
+	}
+
+	values := make([]float64, 0, len(parts))
+	for _, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, &usageError{err: fmt.Errorf("invalid %s %q: %q is not a number", name, value, part)}
+		}
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// parseCoordinates parses coordinates in the lat,lng format.
+func parseCoordinates(name, value string) (*what3words.Coordinates, error) {
+	values, err := parseFloats(name, value, 2)
+	if err != nil {
+		return nil, err
+	}
+	return &what3words.Coordinates{Lat: values[0], Lng: values[1]}, nil
+}
+
+// parseBoundingBox parses a bounding box in the south,west,north,east format.
+func parseBoundingBox(name, value string) (*what3words.BoundingBox, error) {
+	values, err := parseFloats(name, value, 4)
+	if err != nil {
+		return nil, err
+	}
+	return what3words.NewBoundingBox(values[0], values[1], values[2], values[3]), nil
+}
+
+// parseCircle parses a circle in the lat,lng,kilometres format.
+func parseCircle(name, value string) (*what3words.CoordinateRadius, error) {
+	values, err := parseFloats(name, value, 3)
+	if err != nil {
+		return nil, err
+	}
+	return &what3words.CoordinateRadius{
+		Coordinates: what3words.Coordinates{Lat: values[0], Lng: values[1]},
+		Radius:      int(values[2]),
+	}, nil
+}
+
+// parsePolygon parses a polygon in the lat,lng,lat,lng,... format.
+func parsePolygon(name, value string) (what3words.PolygonCoordinates, error) {
+	values, err := parseFloats(name, value, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(values)%2 != 0 {
+		return nil, &usageError{err: fmt.Errorf("invalid %s %q: expected lat,lng pairs", name, value)}
+	}
+
+	polygon := make(what3words.PolygonCoordinates, 0, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		polygon = append(polygon, what3words.Coordinates{Lat: values[i], Lng: values[i+1]})
+	}
+	return polygon, nil
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+// command runs a subcommand with its arguments.
+type command func(ctx context.Context, e *env, name string, args []string) error
+
+// commands contains the subcommands by name.
+var commands = map[string]command{
+	"convert-to-3wa":    convertTo3wa,
+	"convert-to-coords": convertToCoordinates,
+	"autosuggest":       autoSuggest,
+	"grid-section":      gridSection,
+	"languages":         languages,
+	"interactive":       interactive,
+}
+
+// newFlagSet creates the flag set for a subcommand with the common flags.
+func newFlagSet(e *env, name, arguments string) (*flag.FlagSet, *options) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: w3w %s [flags] %s\n\nFlags:\n", name, arguments)
+		fs.PrintDefaults()
+	}
+	return fs, addOptions(fs)
+}
+
+// invocation is a parsed subcommand ready to make requests.
+type invocation struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	client what3words.What3Words
+
+	// args are the positional arguments.
+	args []string
+}
+
+// prepare parses the arguments of a subcommand, checks the number of positional arguments and creates the client
+// and the context for its requests. maxArgs is -1 if there is no maximum.
+func prepare(ctx context.Context, e *env, fs *flag.FlagSet, opts *options, args []string, minArgs, maxArgs int) (*invocation, error) {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(positional) < minArgs:
+		return nil, &usageError{err: errors.New("missing arguments, run with -h for usage")}
+	case maxArgs >= 0 && len(positional) > maxArgs:
+		return nil, &usageError{err: fmt.Errorf("unexpected arguments %q", positional[maxArgs:])}
+	}
+
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := opts.client(e)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+	return &invocation{ctx: ctx, cancel: cancel, client: client, args: positional}, nil
+}
+
+func convertTo3wa(ctx context.Context, e *env, name string, args []string) error {
+	fs, opts := newFlagSet(e, name, "LAT,LNG...")
+	inv, err := prepare(ctx, e, fs, opts, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	defer inv.cancel()
+
+	coordinates := make([]*what3words.Coordinates, 0, len(inv.args))
+	for _, arg := range inv.args {
+		c, err := parseCoordinates("coordinates", arg)
+		if err != nil {
+			return err
+		}
+		coordinates = append(coordinates, c)
+	}
+
+	locations := make([]*what3words.LocationResponse, 0, len(coordinates))
+	for _, c := range coordinates {
+		location, err := inv.client.ConvertTo3wa(inv.ctx, c)
+		if err != nil {
+			return err
+		}
+		locations = append(locations, location)
+	}
+
+	return locationsOutput(locations).write(e.stdout, opts.format)
+}
+
+func convertToCoordinates(ctx context.Context, e *env, name string, args []string) error {
+	fs, opts := newFlagSet(e, name, "WORDS...")
+	inv, err := prepare(ctx, e, fs, opts, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	defer inv.cancel()
+
+	locations := make([]*what3words.LocationResponse, 0, len(inv.args))
+	for _, words := range inv.args {
+		location, err := inv.client.ConvertToCoordinates(inv.ctx, words)
+		if err != nil {
+			return err
+		}
+		locations = append(locations, location)
+	}
+
+	return locationsOutput(locations).write(e.stdout, opts.format)
+}
+
+// autoSuggestFlags are the flags for the fields of what3words.AutoSuggestInput.
+type autoSuggestFlags struct {
+	focus             string
+	clipToCountry     string
+	clipToBoundingBox string
+	clipToCircle      string
+	clipToPolygon     string
+	inputType         string
+	nResults          int
+	nFocusResults     int
+	preferLand        bool
+	withCoordinates   bool
+}
+
+// addAutoSuggestFlags registers the autosuggest flags on fs.
+func addAutoSuggestFlags(fs *flag.FlagSet) *autoSuggestFlags {
+	f := &autoSuggestFlags{}
+	fs.StringVar(&f.focus, "focus", "", "lat,lng to prioritise suggestions near")
+	fs.StringVar(&f.clipToCountry, "clip-to-country", "", "comma separated ISO 3166-1 alpha-2 country codes to restrict suggestions to")
+	fs.StringVar(&f.clipToBoundingBox, "clip-to-bounding-box", "", "south,west,north,east box to restrict suggestions to")
+	fs.StringVar(&f.clipToCircle, "clip-to-circle", "", "lat,lng,kilometres circle to restrict suggestions to")
+	fs.StringVar(&f.clipToPolygon, "clip-to-polygon", "", "lat,lng,lat,lng,... closed polygon to restrict suggestions to")
+	fs.StringVar(&f.inputType, "input-type", "", "input type: text, vocon-hybrid, nmdp-asr or generic-voice")
+	fs.IntVar(&f.nResults, "n-results", 0, "number of suggestions to return (default 3)")
+	fs.IntVar(&f.nFocusResults, "n-focus-results", 0, "number of suggestions which must be close to the focus")
+	fs.BoolVar(&f.preferLand, "prefer-land", true, "prefer suggestions on land")
+	fs.BoolVar(&f.withCoordinates, "with-coordinates", false, "include the coordinates of each suggestion (implied by --format geojson)")
+	return f
+}
+
+// input builds the AutoSuggestInput for the flags. preferLandSet reports whether --prefer-land was given.
+func (f *autoSuggestFlags) input(words, language string, preferLandSet bool) (*what3words.AutoSuggestInput, error) {
+	input := &what3words.AutoSuggestInput{
+		Words:         words,
+		Language:      language,
+		InputType:     what3words.InputType(f.inputType),
+		NResults:      f.nResults,
+		NFocusResults: f.nFocusResults,
+	}
+
+	var err error
+	if f.focus != "" {
+		if input.Focus, err = parseCoordinates("focus", f.focus); err != nil {
+			return nil, err
+		}
+	}
+	if f.clipToCountry != "" {
+		input.ClipToCountry = strings.Split(f.clipToCountry, ",")
+	}
+	if f.clipToBoundingBox != "" {
+		if input.ClipToBoundingBox, err = parseBoundingBox("clip-to-bounding-box", f.clipToBoundingBox); err != nil {
+			return nil, err
+		}
+	}
+	if f.clipToCircle != "" {
+		if input.ClipToCircle, err = parseCircle("clip-to-circle", f.clipToCircle); err != nil {
+			return nil, err
+		}
+	}
+	if f.clipToPolygon != "" {
+		if input.ClipToPolygon, err = parsePolygon("clip-to-polygon", f.clipToPolygon); err != nil {
+			return nil, err
+		}
+	}
+	if preferLandSet {
+		preferLand := f.preferLand
+		input.PreferLand = &preferLand
+	}
+
+	return input, nil
+}
+
+func autoSuggest(ctx context.Context, e *env, name string, args []string) error {
+	fs, opts := newFlagSet(e, name, "INPUT")
+	flags := addAutoSuggestFlags(fs)
+	inv, err := prepare(ctx, e, fs, opts, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	defer inv.cancel()
+
+	preferLandSet := false
+	fs.Visit(func(f *flag.Flag) {
+		preferLandSet = preferLandSet || f.Name == "prefer-land"
+	})
+
+	input, err := flags.input(inv.args[0], opts.language, preferLandSet)
+	if err != nil {
+		return err
+	}
+
+	if flags.withCoordinates || opts.format == formatGeoJSON {
+		resp, err := inv.client.AutoSuggestWithCoordinates(inv.ctx, input)
+		if err != nil {
+			return err
+		}
+		return suggestionsWithCoordinatesOutput(resp).write(e.stdout, opts.format)
+	}
+
+	resp, err := inv.client.AutoSuggest(inv.ctx, input)
+	if err != nil {
+		return err
+	}
+	return suggestionsOutput(resp).write(e.stdout, opts.format)
+}
+
+func gridSection(ctx context.Context, e *env, name string, args []string) error {
+	fs, opts := newFlagSet(e, name, "SOUTH,WEST,NORTH,EAST")
+	inv, err := prepare(ctx, e, fs, opts, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	defer inv.cancel()
+
+	box, err := parseBoundingBox("bounding box", inv.args[0])
+	if err != nil {
+		return err
+	}
+
+	grid, err := inv.client.GridSection(inv.ctx, box)
+	if err != nil {
+		return err
+	}
+
+	return gridOutput(grid).write(e.stdout, opts.format)
+}
+
+func languages(ctx context.Context, e *env, name string, args []string) error {
+	fs, opts := newFlagSet(e, name, "")
+	inv, err := prepare(ctx, e, fs, opts, args, 0, 0)
+	if err != nil {
+		return err
+	}
+	defer inv.cancel()
+
+	if opts.format == formatGeoJSON {
+		return &usageError{err: errors.New("geojson output is not available for languages")}
+	}
+
+	languages, err := inv.client.AvailableLanguages(inv.ctx)
+	if err != nil {
+		return err
+	}
+
+	return languagesOutput(languages).write(e.stdout, opts.format)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+// Output formats.
+const (
+	formatTable   = "table"
+	formatJSON    = "json"
+	formatGeoJSON = "geojson"
+	formatCSV     = "csv"
+)
+
+// env is the environment a command runs in.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+}
+
+// config is the contents of the config file.
+type config struct {
+	APIKey   string `json:"apiKey"`
+	Endpoint string `json:"endpoint"`
+	Language string `json:"language"`
+	Locale   string `json:"locale"`
+}
+
+// options are the flags common to every command.
+type options struct {
+	config   string
+	endpoint string
+	language string
+	locale   string
+	format   string
+	timeout  time.Duration
+}
+
+// addOptions registers the common flags on fs.
+func addOptions(fs *flag.FlagSet) *options {
+	opts := &options{}
+	fs.StringVar(&opts.config, "config", "", "path of the JSON config file (default w3w/config.json in the user config directory)")
+	fs.StringVar(&opts.endpoint, "endpoint", "", "what3words API endpoint, for self-hosted deployments (default https://api.what3words.com/v3)")
+	fs.StringVar(&opts.language, "language", "", "language of 3 word addresses (default en)")
+	fs.StringVar(&opts.locale, "locale", "", "locale of 3 word addresses for languages with several scripts, e.g. mn_la")
+	fs.StringVar(&opts.format, "format", formatTable, "output format: table, json, geojson or csv")
+	fs.DurationVar(&opts.timeout, "timeout", 30*time.Second, "timeout for the whole command, or for each request in interactive mode")
+	return opts
+}
+
+// validate checks the common flags after parsing.
+func (o *options) validate() error {
+	switch o.format {
+	case formatTable, formatJSON, formatGeoJSON, formatCSV:
+		return nil
+	default:
+		return &usageError{err: fmt.Errorf("unknown format %q: must be table, json, geojson or csv", o.format)}
+	}
+}
+
+// client creates a what3words client from the flags, the config file and the environment.
+// Flags take precedence over the environment, which takes precedence over the config file.
+func (o *options) client(e *env) (what3words.What3Words, error) {
+	cfg, err := loadConfig(o.config)
+	if err != nil {
+		return nil, err
+	}
+
+	if key := e.getenv("W3W_API_KEY"); key != "" {
+		cfg.APIKey = key
+	}
+	if o.endpoint != "" {
+		cfg.Endpoint = o.endpoint
+	}
+	if o.language != "" {
+		cfg.Language = o.language
+	}
+	if o.locale != "" {
+		cfg.Locale = o.locale
+	}
+
+	if cfg.APIKey == "" {
+		return nil, errMissingKey
+	}
+
+	var clientOpts []what3words.Option
+	if cfg.Endpoint != "" {
+		endpoint, err := url.Parse(cfg.Endpoint)
+		if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+			return nil, &usageError{err: fmt.Errorf("invalid endpoint %q: must be an absolute URL", cfg.Endpoint)}
+		}
+		clientOpts = append(clientOpts, what3words.WithEndpoint(endpoint))
+	}
+	if cfg.Language != "" {
+		clientOpts = append(clientOpts, what3words.WithLanguage(cfg.Language))
+	}
+	if cfg.Locale != "" {
+		clientOpts = append(clientOpts, what3words.WithLocale(cfg.Locale))
+	}
+
+	return what3words.NewClient(cfg.APIKey, clientOpts...), nil
+}
+
+// loadConfig reads the config file at path. If path is empty the default config file is read if it exists.
+func loadConfig(path string) (config, error) {
+	var cfg config
+
+	explicit := path != ""
+	if !explicit {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return cfg, nil
+		}
+		path = filepath.Join(dir, "w3w", "config.json")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("reading config: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("reading config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
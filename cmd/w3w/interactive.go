@@ -0,0 +1,406 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+const (
+	_prompt = "w3w> "
+
+	// Control keys.
+	_keyInterrupt = 3
+	_keyEOF       = 4
+	_keyBackspace = 8
+	_keyKillLine  = 21
+	_keyEscape    = 27
+	_keyDelete    = 127
+)
+
+const interactiveHelp = `Type a full or partial 3 word address to see suggestions as you type.
+Press Enter to select the first suggestion, or 1-9 to select another.
+Ctrl-U clears the input and Ctrl-D quits. Commands:
+  :focus LAT,LNG      prioritise suggestions near a point (:focus to clear)
+  :country CC,CC      only suggest addresses in the given countries (:country to clear)
+  :report on|off      send selections back to what3words
+  :help               show this help
+  :quit               leave interactive mode`
+
+// interactive runs an interactive autosuggest session in the terminal.
+func interactive(ctx context.Context, e *env, name string, args []string) error {
+	fs, opts := newFlagSet(e, name, "")
+	focus := fs.String("focus", "", "lat,lng to prioritise suggestions near")
+	clipToCountry := fs.String("clip-to-country", "", "comma separated country codes to restrict suggestions to")
+	report := fs.Bool("report", false, "send selections back to what3words")
+	debounce := fs.Duration("debounce", 250*time.Millisecond, "how long to wait after a keystroke before requesting suggestions")
+
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return &usageError{err: fmt.Errorf("unexpected arguments %q", fs.Args())}
+	}
+
+	client, err := opts.client(e)
+	if err != nil {
+		return err
+	}
+
+	s := &session{
+		client:   client,
+		out:      e.stdout,
+		debounce: *debounce,
+		timeout:  opts.timeout,
+		report:   *report,
+		template: what3words.AutoSuggestInput{Language: opts.language},
+	}
+	if *focus != "" {
+		if s.template.Focus, err = parseCoordinates("focus", *focus); err != nil {
+			return err
+		}
+	}
+	if *clipToCountry != "" {
+		s.template.ClipToCountry = strings.Split(strings.ToUpper(*clipToCountry), ",")
+	}
+
+	if f, ok := e.stdin.(*os.File); ok {
+		restore, err := makeRaw(f)
+		if err != nil {
+			return err
+		}
+		defer restore()
+	}
+
+	return s.run(ctx, e.stdin)
+}
+
+// errQuit is returned by session.command when the session should end.
+var errQuit = errors.New("quit")
+
+// session is an interactive autosuggest session. Keystrokes are debounced, so suggestions are only requested once
+// typing pauses, and responses to outdated input are discarded.
+type session struct {
+	client   what3words.What3Words
+	out      io.Writer
+	debounce time.Duration
+	timeout  time.Duration
+	report   bool
+
+	// template holds the session's focus, clipping and language, which are applied to every request.
+	template what3words.AutoSuggestInput
+
+	text        string
+	input       *what3words.AutoSuggestInput
+	suggestions []what3words.Suggestion
+	message     string
+
+	// reporting tracks the selections being reported in the background, which send any errors to reportErrs.
+	reporting  sync.WaitGroup
+	reportErrs chan error
+}
+
+// suggestions is the response to an autosuggest request for version seq of the input.
+type suggestions struct {
+	seq   int
+	input *what3words.AutoSuggestInput
+	resp  *what3words.AutoSuggestResponse
+	err   error
+}
+
+// run reads keystrokes from keys until the session is quit, keys is exhausted or ctx is done.
+func (s *session) run(ctx context.Context, keys io.Reader) error {
+	// Selections still being reported when the session ends are given until their timeout to complete.
+	reportCtx := ctx
+	defer s.reporting.Wait()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.reportErrs = make(chan error)
+
+	keyCh := make(chan byte)
+	go func() {
+		defer close(keyCh)
+		buf := make([]byte, 64)
+		for {
+			n, err := keys.Read(buf)
+			for _, key := range buf[:n] {
+				select {
+				case keyCh <- key:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	results := make(chan suggestions)
+	var (
+		seq         int
+		timer       *time.Timer
+		timerC      <-chan time.Time
+		cancelFetch context.CancelFunc = func() {}
+		escape      int
+	)
+	defer func() { cancelFetch() }()
+
+	s.message = "Type a 3 word address, or :help for help"
+	s.render()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.clear()
+			return nil
+
+		case key, ok := <-keyCh:
+			if !ok {
+				s.clear()
+				return nil
+			}
+
+			// Skip escape sequences, such as those sent by the arrow keys.
+			switch {
+			case escape == 1:
+				escape = 0
+				if key == '[' {
+					escape = 2
+				}
+				continue
+			case escape == 2:
+				if key >= 0x40 && key <= 0x7e {
+					escape = 0
+				}
+				continue
+			}
+
+			previous := s.text
+			switch {
+			case key == _keyInterrupt || (key == _keyEOF && s.text == ""):
+				s.clear()
+				return nil
+			case key == _keyEscape:
+				escape = 1
+			case key == _keyBackspace || key == _keyDelete:
+				if _, size := utf8.DecodeLastRuneInString(s.text); size > 0 {
+					s.text = s.text[:len(s.text)-size]
+				}
+			case key == _keyKillLine:
+				s.text = ""
+			case key == '\r' || key == '\n':
+				if strings.HasPrefix(s.text, ":") {
+					err := s.command(s.text)
+					if errors.Is(err, errQuit) {
+						s.clear()
+						return nil
+					}
+					s.text = ""
+					if err != nil {
+						s.message = err.Error()
+					}
+				} else {
+					s.selectSuggestion(ctx, reportCtx, 0)
+				}
+			case key >= '1' && key <= '9' && !strings.HasPrefix(s.text, ":"):
+				s.selectSuggestion(ctx, reportCtx, int(key-'1'))
+			case key >= ' ':
+				s.text += string(key)
+			}
+
+			if s.text != previous {
+				seq++
+				s.suggestions, s.input = nil, nil
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.NewTimer(s.debounce)
+				timerC = timer.C
+			}
+			s.render()
+
+		case <-timerC:
+			timerC = nil
+			cancelFetch()
+
+			if !strings.HasPrefix(s.text, ":") && suggestible(s.text) {
+				cancelFetch = s.fetch(ctx, seq, results)
+			}
+
+		case err := <-s.reportErrs:
+			s.message = "Reporting selection failed: " + err.Error()
+			s.render()
+
+		case result := <-results:
+			if result.seq != seq {
+				continue
+			}
+			if result.err != nil {
+				s.message = result.err.Error()
+			} else {
+				s.input, s.suggestions = result.input, result.resp.Suggestions
+				s.message = ""
+				if len(s.suggestions) == 0 {
+					s.message = "No suggestions"
+				}
+			}
+			s.render()
+		}
+	}
+}
+
+// fetch requests suggestions for the current input in the background and sends them to results. It returns a
+// function which cancels the request.
+func (s *session) fetch(ctx context.Context, seq int, results chan<- suggestions) context.CancelFunc {
+	input := s.template
+	input.Words = s.text
+
+	fetchCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	go func() {
+		resp, err := s.client.AutoSuggest(fetchCtx, &input)
+		select {
+		case results <- suggestions{seq: seq, input: &input, resp: resp, err: err}:
+		case <-ctx.Done():
+		}
+	}()
+	return cancel
+}
+
+// suggestible reports whether text contains two words and the start of a third, the minimum autosuggest accepts.
+func suggestible(text string) bool {
+	parts := strings.Split(strings.TrimLeft(text, "/"), ".")
+	return len(parts) == 3 && parts[0] != "" && parts[1] != "" && parts[2] != ""
+}
+
+// command runs a session command such as ":focus 51.5,-0.12".
+func (s *session) command(line string) error {
+	fields := strings.Fields(strings.TrimPrefix(line, ":"))
+	if len(fields) == 0 {
+		return nil
+	}
+
+	name, args := fields[0], fields[1:]
+	switch {
+	case name == "quit" || name == "q":
+		return errQuit
+	case name == "help":
+		s.message = strings.ReplaceAll(interactiveHelp, "\n", "\n  ")
+	case name == "focus" && len(args) == 0:
+		s.template.Focus = nil
+		s.message = "Focus cleared"
+	case name == "focus" && len(args) == 1:
+		focus, err := parseCoordinates("focus", args[0])
+		if err != nil {
+			return err
+		}
+		s.template.Focus = focus
+		s.message = "Focus set to " + focus.ToString()
+	case name == "country" && len(args) == 0:
+		s.template.ClipToCountry = nil
+		s.message = "Country clipping cleared"
+	case name == "country" && len(args) == 1:
+		s.template.ClipToCountry = strings.Split(strings.ToUpper(args[0]), ",")
+		s.message = "Suggestions restricted to " + strings.Join(s.template.ClipToCountry, ", ")
+	case name == "report" && len(args) == 1 && (args[0] == "on" || args[0] == "off"):
+		s.report = args[0] == "on"
+		s.message = "Reporting selections " + args[0]
+	default:
+		return fmt.Errorf("unknown command %q, type :help for help", line)
+	}
+
+	return nil
+}
+
+// selectSuggestion resolves the suggestion at index to coordinates, prints it and reports it in the background with
+// reportCtx if enabled.
+func (s *session) selectSuggestion(ctx, reportCtx context.Context, index int) {
+	if index >= len(s.suggestions) {
+		return
+	}
+	suggestion := s.suggestions[index]
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	location, err := s.client.ConvertToCoordinates(ctx, suggestion.Words)
+	if err != nil {
+		s.message = err.Error()
+		return
+	}
+
+	s.message = ""
+	if s.report {
+		s.reporting.Add(1)
+		go s.reportSelection(ctx, reportCtx, s.input, suggestion, index+1)
+	}
+
+	s.clear()
+	fmt.Fprintf(s.out, "///%s  %s,%s  %s\r\n",
+		location.Words, formatFloat(location.Coordinates.Lat), formatFloat(location.Coordinates.Lng), location.Map)
+
+	s.text, s.input, s.suggestions = "", nil, nil
+}
+
+// reportSelection reports the suggestion the user selected at the 1-based rank, so that a slow report does not hold
+// up typing. Errors are sent to reportErrs until ctx, the context of the session, is done.
+func (s *session) reportSelection(ctx, reportCtx context.Context, input *what3words.AutoSuggestInput, suggestion what3words.Suggestion, rank int) {
+	defer s.reporting.Done()
+
+	reportCtx, cancel := context.WithTimeout(reportCtx, s.timeout)
+	defer cancel()
+
+	if err := s.client.ReportSelection(reportCtx, input, suggestion, rank); err != nil {
+		select {
+		case s.reportErrs <- err:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// render redraws the prompt, the suggestions and the message below the cursor, leaving the cursor after the input.
+func (s *session) render() {
+	var b strings.Builder
+	b.WriteString("\r\033[J")
+	b.WriteString(_prompt + s.text)
+
+	lines := 0
+	for i, suggestion := range s.suggestions {
+		fmt.Fprintf(&b, "\r\n  %d. ///%s", i+1, suggestion.Words)
+		if suggestion.NearestPlace != "" {
+			fmt.Fprintf(&b, "  %s, %s", suggestion.NearestPlace, suggestion.Country)
+		}
+		if suggestion.DistanceToFocusKm != 0 {
+			fmt.Fprintf(&b, "  %dkm", suggestion.DistanceToFocusKm)
+		}
+		lines++
+	}
+	if s.message != "" {
+		b.WriteString("\r\n  " + strings.ReplaceAll(s.message, "\n", "\r\n"))
+		lines += 1 + strings.Count(s.message, "\n")
+	}
+
+	if lines > 0 {
+		fmt.Fprintf(&b, "\033[%dA", lines)
+	}
+	b.WriteString("\r")
+	if column := utf8.RuneCountInString(_prompt + s.text); column > 0 {
+		fmt.Fprintf(&b, "\033[%dC", column)
+	}
+
+	_, _ = io.WriteString(s.out, b.String())
+}
+
+// clear removes the prompt, suggestions and message from the screen.
+func (s *session) clear() {
+	_, _ = io.WriteString(s.out, "\r\033[J")
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+	"github.com/henrwal/w3w-go-wrapper/w3wtest"
+)
+
+// syncBuffer is a bytes.Buffer which can be written and read concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// interactiveSession runs the interactive command with args in the background. Keystrokes are written to the
+// returned writer, and the exit code is sent to the returned channel.
+func interactiveSession(t *testing.T, stdout io.Writer, args ...string) (*io.PipeWriter, <-chan int) {
+	stdin, keys := io.Pipe()
+	t.Cleanup(func() { _ = keys.Close() })
+
+	code := make(chan int, 1)
+	go func() {
+		code <- run(context.Background(), append([]string{"interactive", "--debounce", "20ms"}, args...), stdin, stdout,
+			&bytes.Buffer{}, getenv(map[string]string{"W3W_API_KEY": "test-key"}))
+	}()
+	return keys, code
+}
+
+func requestsTo(fake *w3wtest.Server, endpoint string) []w3wtest.Request {
+	var requests []w3wtest.Request
+	for _, r := range fake.Requests() {
+		if r.Endpoint == endpoint {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+func TestInteractive(t *testing.T) {
+	fake := newFakeServer(t)
+	var stdout syncBuffer
+	keys, code := interactiveSession(t, &stdout, "--endpoint", fake.URL().String(), "--report")
+
+	// Keystrokes typed faster than the debounce interval make a single request.
+	_, _ = io.WriteString(keys, "filled.count.so")
+	assert.Eventually(t, func() bool {
+		return strings.Contains(stdout.String(), "1. ///filled.count.soap  Bayswater, London, GB")
+	}, time.Second, 10*time.Millisecond)
+	if assert.Len(t, requestsTo(fake, w3wtest.EndpointAutoSuggest), 1) {
+		assert.Equal(t, "filled.count.so", requestsTo(fake, w3wtest.EndpointAutoSuggest)[0].Query.Get("input"))
+	}
+
+	_, _ = io.WriteString(keys, "1")
+	assert.Eventually(t, func() bool {
+		return strings.Contains(stdout.String(), "///filled.count.soap  51.520847,-0.195521  https://w3w.co/filled.count.soap\r\n")
+	}, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return len(requestsTo(fake, w3wtest.EndpointAutoSuggestSelection)) == 1
+	}, time.Second, 10*time.Millisecond)
+	if selections := requestsTo(fake, w3wtest.EndpointAutoSuggestSelection); assert.Len(t, selections, 1) {
+		assert.Equal(t, "filled.count.so", selections[0].Query.Get("raw-input"))
+		assert.Equal(t, "filled.count.soap", selections[0].Query.Get("selection"))
+		assert.Equal(t, "1", selections[0].Query.Get("rank"))
+	}
+
+	_, _ = io.WriteString(keys, ":quit\r")
+	select {
+	case c := <-code:
+		assert.Equal(t, exitOK, c)
+	case <-time.After(time.Second):
+		t.Fatal("interactive mode did not quit")
+	}
+}
+
+func TestInteractive_SessionSettings(t *testing.T) {
+	fake := newFakeServer(t)
+	var stdout syncBuffer
+	keys, code := interactiveSession(t, &stdout, "--endpoint", fake.URL().String(), "--clip-to-country", "fr")
+
+	// The settings apply to every request until they are changed. Backspace removes the last character.
+	_, _ = io.WriteString(keys, ":focus 51.5,-0.1\r:country gb\rfilled.count.soapx\x7f")
+	assert.Eventually(t, func() bool {
+		return strings.Contains(stdout.String(), "1. ///filled.count.soap")
+	}, time.Second, 10*time.Millisecond)
+
+	requests := requestsTo(fake, w3wtest.EndpointAutoSuggest)
+	if assert.Len(t, requests, 1) {
+		assert.Equal(t, "filled.count.soap", requests[0].Query.Get("input"))
+		assert.Equal(t, "51.500000,-0.100000", requests[0].Query.Get("focus"))
+		assert.Equal(t, "GB", requests[0].Query.Get("clip-to-country"))
+	}
+
+	// Without --report selections are not sent back, and closing the input ends the session.
+	_, _ = io.WriteString(keys, "\r")
+	assert.Eventually(t, func() bool {
+		return strings.Contains(stdout.String(), "https://w3w.co/filled.count.soap\r\n")
+	}, time.Second, 10*time.Millisecond)
+	_ = keys.Close()
+	assert.Equal(t, exitOK, <-code)
+	assert.Empty(t, requestsTo(fake, w3wtest.EndpointAutoSuggestSelection))
+}
+
+// slowReporter converts every address to the same location and holds reports of selections until release is closed.
+type slowReporter struct {
+	what3words.What3Words
+
+	ranks   chan int
+	release chan struct{}
+}
+
+func (r *slowReporter) ConvertToCoordinates(_ context.Context, words string, _ ...what3words.CallOption) (*what3words.LocationResponse, error) {
+	return &what3words.LocationResponse{Words: words, Map: "https://w3w.co/" + words}, nil
+}
+
+func (r *slowReporter) ReportSelection(ctx context.Context, _ *what3words.AutoSuggestInput, _ what3words.Suggestion, rank int, _ ...what3words.CallOption) error {
+	r.ranks <- rank
+	select {
+	case <-r.release:
+		return errors.New("report rejected")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestSession_ReportSelection(t *testing.T) {
+	client := &slowReporter{ranks: make(chan int, 1), release: make(chan struct{})}
+	var stdout syncBuffer
+	s := &session{
+		client:   client,
+		out:      &stdout,
+		debounce: time.Hour,
+		timeout:  5 * time.Second,
+		report:   true,
+		input:    &what3words.AutoSuggestInput{Words: "filled.count.so"},
+		// Suggestions without a rank are reported by the position they were shown in.
+		suggestions: []what3words.Suggestion{{Words: "filled.count.soap"}, {Words: "filled.count.soaps"}},
+	}
+
+	keys, typing := io.Pipe()
+	done := make(chan error, 1)
+	go func() { done <- s.run(context.Background(), keys) }()
+
+	_, _ = io.WriteString(typing, "2")
+	assert.Equal(t, 2, <-client.ranks)
+
+	// Typing carries on while the report is outstanding.
+	_, _ = io.WriteString(typing, "index")
+	assert.Eventually(t, func() bool {
+		return strings.Contains(stdout.String(), _prompt+"index")
+	}, time.Second, 10*time.Millisecond)
+
+	close(client.release)
+	assert.Eventually(t, func() bool {
+		return strings.Contains(stdout.String(), "Reporting selection failed: report rejected")
+	}, time.Second, 10*time.Millisecond)
+
+	_ = typing.Close()
+	assert.NoError(t, <-done)
+}
+
+func TestSession_Command(t *testing.T) {
+	tests := map[string]struct {
+		line            string
+		expectedErr     string
+		expectedMessage string
+		expectedInput   what3words.AutoSuggestInput
+		expectedReport  bool
+	}{
+		"set focus": {
+			line:            ":focus 51.5,-0.1",
+			expectedMessage: "Focus set to 51.500000,-0.100000",
+			expectedInput:   what3words.AutoSuggestInput{Focus: &what3words.Coordinates{Lat: 51.5, Lng: -0.1}, ClipToCountry: []string{"FR"}},
+		},
+		"clear focus": {
+			line:            ":focus",
+			expectedMessage: "Focus cleared",
+			expectedInput:   what3words.AutoSuggestInput{ClipToCountry: []string{"FR"}},
+		},
+		"set countries": {
+			line:            ":country gb,ie",
+			expectedMessage: "Suggestions restricted to GB, IE",
+			expectedInput:   what3words.AutoSuggestInput{Focus: &what3words.Coordinates{Lat: 1, Lng: 2}, ClipToCountry: []string{"GB", "IE"}},
+		},
+		"clear countries": {
+			line:            ":country",
+			expectedMessage: "Country clipping cleared",
+			expectedInput:   what3words.AutoSuggestInput{Focus: &what3words.Coordinates{Lat: 1, Lng: 2}},
+		},
+		"report on": {
+			line:            ":report on",
+			expectedMessage: "Reporting selections on",
+			expectedInput:   what3words.AutoSuggestInput{Focus: &what3words.Coordinates{Lat: 1, Lng: 2}, ClipToCountry: []string{"FR"}},
+			expectedReport:  true,
+		},
+		"invalid focus": {
+			line:          ":focus north",
+			expectedErr:   "invalid focus \"north\": expected 2 comma separated numbers",
+			expectedInput: what3words.AutoSuggestInput{Focus: &what3words.Coordinates{Lat: 1, Lng: 2}, ClipToCountry: []string{"FR"}},
+		},
+		"unknown command": {
+			line:          ":zoom 3",
+			expectedErr:   "unknown command \":zoom 3\", type :help for help",
+			expectedInput: what3words.AutoSuggestInput{Focus: &what3words.Coordinates{Lat: 1, Lng: 2}, ClipToCountry: []string{"FR"}},
+		},
+		"quit": {
+			line:          ":quit",
+			expectedErr:   errQuit.Error(),
+			expectedInput: what3words.AutoSuggestInput{Focus: &what3words.Coordinates{Lat: 1, Lng: 2}, ClipToCountry: []string{"FR"}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := &session{
+				template: what3words.AutoSuggestInput{Focus: &what3words.Coordinates{Lat: 1, Lng: 2}, ClipToCountry: []string{"FR"}},
+			}
+
+			err := s.command(tt.line)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedMessage, s.message)
+			assert.Equal(t, tt.expectedInput, s.template)
+			assert.Equal(t, tt.expectedReport, s.report)
+		})
+	}
+}
+
+func TestSuggestible(t *testing.T) {
+	assert.True(t, suggestible("filled.count.s"))
+	assert.True(t, suggestible("///filled.count.soap"))
+	assert.False(t, suggestible("filled.count."))
+	assert.False(t, suggestible("filled.count"))
+	assert.False(t, suggestible("filled..soap"))
+}
@@ -0,0 +1,147 @@
+// Command w3w looks up 3 word addresses from a terminal.
+//
+// Usage:
+//
+//	w3w <command> [flags] [arguments]
+//
+// The commands are:
+//
+//	convert-to-3wa     convert lat,lng coordinates to 3 word addresses
+//	convert-to-coords  convert 3 word addresses to coordinates
+//	autosuggest        suggest 3 word addresses for a full or partial input
+//	grid-section       print the grid lines within a south,west,north,east bounding box
+//	languages          list the available 3 word address languages
+//	interactive        suggest 3 word addresses as they are typed
+//
+// The API key is read from the W3W_API_KEY environment variable, or from the apiKey field of the JSON config file
+// given by --config, which defaults to w3w/config.json in the user's config directory. The config file may also set
+// the endpoint, language and locale. Results are printed as a table, or as JSON, GeoJSON or CSV with --format.
+//
+// The exit status is 0 on success, 2 for invalid flags or arguments, 3 if what3words rejected the input, such as
+// unknown words, 4 if the API key is missing, invalid or over quota, 5 if what3words could not be reached or failed,
+// and 1 for any other error.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+// Exit codes.
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitBadInput    = 3
+	exitAuth        = 4
+	exitUnavailable = 5
+)
+
+const usage = `Usage: w3w <command> [flags] [arguments]
+
+Commands:
+  convert-to-3wa LAT,LNG...          convert coordinates to 3 word addresses
+  convert-to-coords WORDS...         convert 3 word addresses to coordinates
+  autosuggest INPUT                  suggest 3 word addresses for a full or partial input
+  grid-section SOUTH,WEST,NORTH,EAST print the grid lines within a bounding box
+  languages                          list the available 3 word address languages
+  interactive                        suggest 3 word addresses as they are typed
+
+Run "w3w <command> -h" for the flags of a command. Use -- before arguments starting with a minus sign
+if they could be mistaken for flags.
+`
+
+// usageError is returned for invalid flags or arguments.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// errMissingKey is returned when no API key is configured.
+var errMissingKey = errors.New("no API key: set W3W_API_KEY or apiKey in the config file")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.Getenv))
+}
+
+// run runs the command in args and returns the exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+			fmt.Fprint(stdout, usage)
+			return exitOK
+		}
+		fmt.Fprintf(stderr, "w3w: unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+
+	env := &env{stdin: stdin, stdout: stdout, stderr: stderr, getenv: getenv}
+	if err := cmd(ctx, env, args[0], args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		fmt.Fprintf(stderr, "w3w %s: %v\n", args[0], err)
+		return exitCode(err)
+	}
+
+	return exitOK
+}
+
+// exitCode returns the exit code for an error.
+func exitCode(err error) int {
+	var (
+		usageErr      *usageError
+		apiErr        *what3words.APIError
+		validationErr *what3words.ValidationError
+		urlErr        *url.Error
+	)
+
+	switch {
+	case errors.As(err, &usageErr):
+		return exitUsage
+	case errors.Is(err, errMissingKey),
+		errors.Is(err, what3words.ErrMissingKey),
+		errors.Is(err, what3words.ErrInvalidKey),
+		errors.Is(err, what3words.ErrSuspendedKey),
+		errors.Is(err, what3words.ErrInvalidReferer),
+		errors.Is(err, what3words.ErrQuotaExceeded):
+		return exitAuth
+	case errors.As(err, &validationErr):
+		return exitBadInput
+	case errors.As(err, &apiErr):
+		if apiErr.Temporary() {
+			return exitUnavailable
+		}
+		return exitBadInput
+	case errors.Is(err, what3words.ErrRateLimited),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &urlErr):
+		return exitUnavailable
+	default:
+		return exitError
+	}
+}
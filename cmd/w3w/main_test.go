@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+	"github.com/henrwal/w3w-go-wrapper/w3wtest"
+)
+
+var filledCountSoap = what3words.LocationResponse{
+	Coordinates:  what3words.Coordinates{Lat: 51.520847, Lng: -0.195521},
+	Country:      "GB",
+	Language:     "en",
+	Map:          "https://w3w.co/filled.count.soap",
+	NearestPlace: "Bayswater, London",
+	Square: what3words.Square{
+		Northeast: what3words.Coordinates{Lat: 51.52086, Lng: -0.195499},
+		Southwest: what3words.Coordinates{Lat: 51.520833, Lng: -0.195543},
+	},
+	Words: "filled.count.soap",
+}
+
+func newFakeServer(t *testing.T) *w3wtest.Server {
+	fake := w3wtest.NewServer()
+	t.Cleanup(fake.Close)
+
+	fake.AddLocation(filledCountSoap)
+	fake.AddGridSection(*what3words.NewBoundingBox(51.5208, -0.1956, 51.5209, -0.1955), what3words.GridSection{
+		Lines: []what3words.GridLine{{
+			Start: what3words.Coordinates{Lat: 51.520833, Lng: -0.1956},
+			End:   what3words.Coordinates{Lat: 51.520833, Lng: -0.1955},
+		}},
+	})
+	return fake
+}
+
+func getenv(values map[string]string) func(string) string {
+	return func(key string) string {
+		return values[key]
+	}
+}
+
+func TestRun(t *testing.T) {
+	fake := newFakeServer(t)
+	endpoint := fake.URL().String()
+
+	dir := t.TempDir()
+	withKey := filepath.Join(dir, "with-key.json")
+	assert.NoError(t, os.WriteFile(withKey, []byte(`{"apiKey": "config-key", "endpoint": "`+endpoint+`"}`), 0o600))
+	withoutKey := filepath.Join(dir, "without-key.json")
+	assert.NoError(t, os.WriteFile(withoutKey, []byte(`{"endpoint": "`+endpoint+`"}`), 0o600))
+
+	tests := map[string]struct {
+		args           []string
+		env            map[string]string
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+	}{
+		"convert to 3wa as table": {
+			args:         []string{"convert-to-3wa", "--endpoint", endpoint, "51.52085,-0.19552"},
+			env:          map[string]string{"W3W_API_KEY": "test-key"},
+			expectedCode: exitOK,
+			expectedStdout: "WORDS              LAT        LNG        COUNTRY  NEARESTPLACE       MAP\n" +
+				"filled.count.soap  51.520847  -0.195521  GB       Bayswater, London  https://w3w.co/filled.count.soap\n",
+		},
+		"convert to coordinates as csv with flags after arguments": {
+			args:         []string{"convert-to-coords", "filled.count.soap", "--format=csv", "--endpoint", endpoint},
+			env:          map[string]string{"W3W_API_KEY": "test-key"},
+			expectedCode: exitOK,
+			expectedStdout: "words,lat,lng,country,nearestPlace,map\n" +
+				"filled.count.soap,51.520847,-0.195521,GB,\"Bayswater, London\",https://w3w.co/filled.count.soap\n",
+		},
+		"autosuggest as csv": {
+			args:         []string{"autosuggest", "--endpoint", endpoint, "--format", "csv", "--clip-to-country", "GB", "--n-results", "2", "filled.count.so"},
+			env:          map[string]string{"W3W_API_KEY": "test-key"},
+			expectedCode: exitOK,
+			expectedStdout: "rank,words,country,nearestPlace,distanceToFocusKm\n" +
+				"1,filled.count.soap,GB,\"Bayswater, London\",0\n",
+		},
+		"grid section as csv": {
+			args:         []string{"grid-section", "--endpoint", endpoint, "--format", "csv", "51.5208,-0.1956,51.5209,-0.1955"},
+			env:          map[string]string{"W3W_API_KEY": "test-key"},
+			expectedCode: exitOK,
+			expectedStdout: "startLat,startLng,endLat,endLng\n" +
+				"51.520833,-0.1956,51.520833,-0.1955\n",
+		},
+		"languages as table": {
+			args:         []string{"languages", "--endpoint", endpoint},
+			env:          map[string]string{"W3W_API_KEY": "test-key"},
+			expectedCode: exitOK,
+			expectedStdout: "CODE  NAME     NATIVENAME\n" +
+				"en    English  English\n",
+		},
+		"bad words": {
+			args:           []string{"convert-to-coords", "--endpoint", endpoint, "index.home.raft"},
+			env:            map[string]string{"W3W_API_KEY": "test-key"},
+			expectedCode:   exitBadInput,
+			expectedStderr: "w3w convert-to-coords: converting w3w to coordinates: convert-to-coordinates returned status 400: BadWords: words must be a valid 3 word address, such as filled.count.soap or ///filled.count.soap\n",
+		},
+		"invalid words syntax": {
+			args:           []string{"convert-to-coords", "--endpoint", endpoint, "not-an-address"},
+			env:            map[string]string{"W3W_API_KEY": "test-key"},
+			expectedCode:   exitBadInput,
+			expectedStderr: "w3w convert-to-coords: converting w3w to coordinates: invalid words: \"not-an-address\" is not a valid 3 word address\n",
+		},
+		"api key and endpoint from config file": {
+			args:         []string{"languages", "--config", withKey, "--format", "csv"},
+			expectedCode: exitOK,
+			expectedStdout: "code,name,nativeName\n" +
+				"en,English,English\n",
+		},
+		"missing api key": {
+			args:           []string{"languages", "--config", withoutKey},
+			expectedCode:   exitAuth,
+			expectedStderr: "w3w languages: no API key: set W3W_API_KEY or apiKey in the config file\n",
+		},
+		"missing config file": {
+			args:           []string{"languages", "--config", filepath.Join(dir, "missing.json")},
+			expectedCode:   exitError,
+			expectedStderr: "w3w languages: reading config: open " + filepath.Join(dir, "missing.json") + ": no such file or directory\n",
+		},
+		"unknown format": {
+			args:           []string{"languages", "--format", "xml"},
+			env:            map[string]string{"W3W_API_KEY": "test-key"},
+			expectedCode:   exitUsage,
+			expectedStderr: "w3w languages: unknown format \"xml\": must be table, json, geojson or csv\n",
+		},
+		"missing arguments": {
+			args:           []string{"convert-to-3wa", "--endpoint", endpoint},
+			env:            map[string]string{"W3W_API_KEY": "test-key"},
+			expectedCode:   exitUsage,
+			expectedStderr: "w3w convert-to-3wa: missing arguments, run with -h for usage\n",
+		},
+		"invalid coordinates": {
+			args:           []string{"convert-to-3wa", "--endpoint", endpoint, "north,-0.19"},
+			env:            map[string]string{"W3W_API_KEY": "test-key"},
+			expectedCode:   exitUsage,
+			expectedStderr: "w3w convert-to-3wa: invalid coordinates \"north,-0.19\": \"north\" is not a number\n",
+		},
+		"unknown command": {
+			args:           []string{"convert"},
+			expectedCode:   exitUsage,
+			expectedStderr: "w3w: unknown command \"convert\"\n\n" + usage,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(context.Background(), tt.args, nil, &stdout, &stderr, getenv(tt.env))
+
+			assert.Equal(t, tt.expectedCode, code)
+			assert.Equal(t, tt.expectedStdout, stdout.String())
+			assert.Equal(t, tt.expectedStderr, stderr.String())
+		})
+	}
+}
+
+func TestRun_ExitCodes(t *testing.T) {
+	fake := newFakeServer(t)
+	env := getenv(map[string]string{"W3W_API_KEY": "test-key"})
+
+	fake.SetError(w3wtest.EndpointConvertToCoordinates, http.StatusPaymentRequired, what3words.ErrQuotaExceeded, "Quota Exceeded")
+	code := run(context.Background(), []string{"convert-to-coords", "--endpoint", fake.URL().String(), "filled.count.soap"}, nil, &bytes.Buffer{}, &bytes.Buffer{}, env)
+	assert.Equal(t, exitAuth, code)
+
+	fake.SetError(w3wtest.EndpointConvertToCoordinates, http.StatusInternalServerError, what3words.ErrInternalServerError, "Server Error")
+	code = run(context.Background(), []string{"convert-to-coords", "--endpoint", fake.URL().String(), "filled.count.soap"}, nil, &bytes.Buffer{}, &bytes.Buffer{}, env)
+	assert.Equal(t, exitUnavailable, code)
+
+	endpoint := fake.URL().String()
+	fake.Close()
+	code = run(context.Background(), []string{"convert-to-coords", "--endpoint", endpoint, "filled.count.soap"}, nil, &bytes.Buffer{}, &bytes.Buffer{}, env)
+	assert.Equal(t, exitUnavailable, code)
+}
+
+func TestRun_GeoJSON(t *testing.T) {
+	fake := newFakeServer(t)
+	env := getenv(map[string]string{"W3W_API_KEY": "test-key"})
+
+	var stdout bytes.Buffer
+	code := run(context.Background(), []string{"autosuggest", "--endpoint", fake.URL().String(), "--format", "geojson", "filled.count.so"}, nil, &stdout, &bytes.Buffer{}, env)
+	assert.Equal(t, exitOK, code)
+
+	var collection what3words.FeatureCollection
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &collection))
+	if assert.Len(t, collection.Features, 1) {
+		assert.Equal(t, filledCountSoap.Feature().Geometry, collection.Features[0].Geometry)
+		assert.Equal(t, "filled.count.soap", collection.Features[0].Properties["words"])
+	}
+
+	requests := fake.Requests()
+	assert.Equal(t, w3wtest.EndpointAutoSuggestWithCoordinates, requests[len(requests)-1].Endpoint)
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := map[string]struct {
+		args               []string
+		expectedPositional []string
+		expectedFocus      string
+		expectedWith       bool
+	}{
+		"flags before arguments":    {args: []string{"--focus", "-33.8,151.2", "a.b.c"}, expectedPositional: []string{"a.b.c"}, expectedFocus: "-33.8,151.2"},
+		"flags after arguments":     {args: []string{"a.b.c", "-focus=1,2", "--with-coordinates"}, expectedPositional: []string{"a.b.c"}, expectedFocus: "1,2", expectedWith: true},
+		"negative coordinates":      {args: []string{"-33.8,151.2", "-.5,1"}, expectedPositional: []string{"-33.8,151.2", "-.5,1"}},
+		"arguments after separator": {args: []string{"--with-coordinates", "--", "--focus"}, expectedPositional: []string{"--focus"}, expectedWith: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fs, _ := newFlagSet(&env{stderr: &bytes.Buffer{}}, "test", "")
+			flags := addAutoSuggestFlags(fs)
+
+			positional, err := parseArgs(fs, tt.args)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedPositional, positional)
+			assert.Equal(t, tt.expectedFocus, flags.focus)
+			assert.Equal(t, tt.expectedWith, flags.withCoordinates)
+		})
+	}
+}
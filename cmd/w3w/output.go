@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+// output is the result of a command, which can be written in any of the output formats.
+type output struct {
+	// header and rows are written as a table or CSV.
+	header []string
+	rows   [][]string
+
+	// value is written as JSON.
+	value interface{}
+
+	// features is written as GeoJSON. It is nil if the result has no geometry.
+	features *what3words.FeatureCollection
+}
+
+// write writes the output in the format.
+func (o *output) write(w io.Writer, format string) error {
+	switch format {
+	case formatJSON:
+		return writeJSON(w, o.value)
+	case formatGeoJSON:
+		if o.features == nil {
+			return &usageError{err: errors.New("geojson output is not available for this command")}
+		}
+		return writeJSON(w, o.features)
+	case formatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(o.header); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(o.rows); err != nil {
+			return fmt.Errorf("writing CSV: %w", err)
+		}
+		return nil
+	default:
+		writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, strings.ToUpper(strings.Join(o.header, "\t")))
+		for _, row := range o.rows {
+			fmt.Fprintln(writer, strings.Join(row, "\t"))
+		}
+		if err := writer.Flush(); err != nil {
+			return fmt.Errorf("writing table: %w", err)
+		}
+		return nil
+	}
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("writing JSON: %w", err)
+	}
+	return nil
+}
+
+// formatFloat formats a coordinate without trailing zeros.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// locationsOutput returns the output for converted locations.
+func locationsOutput(locations []*what3words.LocationResponse) *output {
+	o := &output{
+		header:   []string{"words", "lat", "lng", "country", "nearestPlace", "map"},
+		value:    locations,
+		features: what3words.NewFeatureCollection(),
+	}
+	if len(locations) == 1 {
+		o.value = locations[0]
+	}
+
+	for _, location := range locations {
+		o.rows = append(o.rows, []string{
+			location.Words,
+			formatFloat(location.Coordinates.Lat),
+			formatFloat(location.Coordinates.Lng),
+			location.Country,
+			location.NearestPlace,
+			location.Map,
+		})
+		o.features.Features = append(o.features.Features, location.Feature())
+	}
+
+	return o
+}
+
+// suggestionsOutput returns the output for autosuggest results without coordinates.
+func suggestionsOutput(resp *what3words.AutoSuggestResponse) *output {
+	o := &output{
+		header: []string{"rank", "words", "country", "nearestPlace", "distanceToFocusKm"},
+		value:  resp,
+	}
+
+	for _, s := range resp.Suggestions {
+		o.rows = append(o.rows, []string{
+			strconv.Itoa(s.Rank), s.Words, s.Country, s.NearestPlace, strconv.Itoa(s.DistanceToFocusKm),
+		})
+	}
+
+	return o
+}
+
+// suggestionsWithCoordinatesOutput returns the output for autosuggest results with coordinates.
+func suggestionsWithCoordinatesOutput(resp *what3words.AutoSuggestWithCoordinatesResponse) *output {
+	o := &output{
+		header:   []string{"rank", "words", "lat", "lng", "country", "nearestPlace", "distanceToFocusKm"},
+		value:    resp,
+		features: what3words.NewFeatureCollection(),
+	}
+
+	for _, s := range resp.Suggestions {
+		o.rows = append(o.rows, []string{
+			strconv.Itoa(s.Rank),
+			s.Words,
+			formatFloat(s.Coordinates.Lat),
+			formatFloat(s.Coordinates.Lng),
+			s.Country,
+			s.NearestPlace,
+			strconv.Itoa(s.DistanceToFocusKm),
+		})
+
+		feature := what3words.LocationResponse{
+			Coordinates:  s.Coordinates,
+			Country:      s.Country,
+			Language:     s.Language,
+			Map:          s.Map,
+			NearestPlace: s.NearestPlace,
+			Square:       s.Square,
+			Words:        s.Words,
+		}.Feature()
+		feature.Properties["rank"] = s.Rank
+		o.features.Features = append(o.features.Features, feature)
+	}
+
+	return o
+}
+
+// gridOutput returns the output for a grid section.
+func gridOutput(grid *what3words.GridSection) *output {
+	o := &output{
+		header:   []string{"startLat", "startLng", "endLat", "endLng"},
+		value:    grid,
+		features: what3words.NewFeatureCollection(grid.Feature()),
+	}
+
+	for _, line := range grid.Lines {
+		o.rows = append(o.rows, []string{
+			formatFloat(line.Start.Lat), formatFloat(line.Start.Lng), formatFloat(line.End.Lat), formatFloat(line.End.Lng),
+		})
+	}
+
+	return o
+}
+
+// languagesOutput returns the output for the available languages. Table and CSV output list the locales of a
+// language after it.
+func languagesOutput(languages []what3words.Language) *output {
+	o := &output{
+		header: []string{"code", "name", "nativeName"},
+		value:  languages,
+	}
+
+	for _, language := range languages {
+		o.rows = append(o.rows, []string{language.Code, language.Name, language.NativeName})
+		for _, locale := range language.Locales {
+			o.rows = append(o.rows, []string{locale.Code, locale.Name, locale.NativeName})
+		}
+	}
+
+	return o
+}
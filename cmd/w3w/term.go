@@ -0,0 +1,34 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// makeRaw switches the terminal f to unbuffered input without echo, so keystrokes can be read as they are typed,
+// and returns a function which restores the previous settings. Interrupts still raise SIGINT.
+func makeRaw(f *os.File) (func(), error) {
+	state, err := stty(f, "-g")
+	if err != nil {
+		return nil, fmt.Errorf("interactive mode needs a terminal: %w", err)
+	}
+	if _, err := stty(f, "-icanon", "-echo", "min", "1"); err != nil {
+		return nil, fmt.Errorf("configuring terminal: %w", err)
+	}
+
+	return func() {
+		_, _ = stty(f, strings.TrimSpace(state))
+	}, nil
+}
+
+// stty runs stty with f as the terminal and returns its output.
+func stty(f *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = f
+	out, err := cmd.Output()
+	return string(out), err
+}
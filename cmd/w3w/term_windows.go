@@ -0,0 +1,11 @@
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// makeRaw is not supported on Windows, where the console has no stty.
+func makeRaw(*os.File) (func(), error) {
+	return nil, errors.New("interactive mode is not supported on Windows")
+}
@@ -5,6 +5,14 @@ import (
 	"strings"
 )
 
+const (
+	// _minPolygonVertices is the fewest coordinates a closed polygon can be given by: a triangle and its first point repeated.
+	_minPolygonVertices = 4
+
+	// _maxPolygonVertices is the most coordinates the API accepts for a polygon.
+	_maxPolygonVertices = 25
+)
+
 // CoordinateRadius represents a circle specified by a central point (coordinates)
 // and a radius (in kilometers). This is used to restrict search results to a specific area.
 type CoordinateRadius struct {
@@ -18,8 +26,24 @@ func (r CoordinateRadius) ToString() string {
 	return fmt.Sprintf("%s,%d", r.Coordinates.ToString(), r.Radius)
 }
 
+// Validate checks that the centre of the circle is valid and the radius is positive.
+// It returns a *ValidationError describing the first problem found.
+func (r CoordinateRadius) Validate() error {
+	return r.validate("circle")
+}
+
+func (r CoordinateRadius) validate(field string) error {
+	if err := r.Coordinates.validate(field); err != nil {
+		return err
+	}
+	if r.Radius <= 0 {
+		return &ValidationError{Field: field, Message: fmt.Sprintf("radius %d must be greater than 0", r.Radius)}
+	}
+	return nil
+}
+
 // PolygonCoordinates represents a series of coordinate points used to define a polygon.
-// The maximum number of coordinate points is 25 for auto-suggestion.
+// The polygon must be closed by repeating the first point as the last, giving between 4 and 25 points.
 type PolygonCoordinates []Coordinates
 
 // ToString method converts the PolygonCoordinates to a string representation
@@ -31,6 +55,32 @@ func (p PolygonCoordinates) ToString() string {
 	return strings.Join(polygon, ",")
 }
 
+// Validate checks that the polygon is closed, has between 4 and 25 points and that every point is valid.
+// It returns a *ValidationError describing the first problem found.
+func (p PolygonCoordinates) Validate() error {
+	return p.validate("polygon")
+}
+
+func (p PolygonCoordinates) validate(field string) error {
+	switch {
+	case len(p) < _minPolygonVertices:
+		return &ValidationError{Field: field, Message: fmt.Sprintf("must have at least %d coordinates, got %d", _minPolygonVertices, len(p))}
+	case len(p) > _maxPolygonVertices:
+		return &ValidationError{Field: field, Message: fmt.Sprintf("is limited to %d coordinates, got %d", _maxPolygonVertices, len(p))}
+	}
+
+	for i, c := range p {
+		if problem := c.problem(); problem != "" {
+			return &ValidationError{Field: field, Message: fmt.Sprintf("point %d: %s", i+1, problem)}
+		}
+	}
+
+	if p[0] != p[len(p)-1] {
+		return &ValidationError{Field: field, Message: "must be closed, with the first coordinates repeated as the last"}
+	}
+	return nil
+}
+
 // GridLine contains start and end coordinates of a line
 type GridLine struct {
 	Start Coordinates `json:"start"`
@@ -54,6 +104,7 @@ type LocationResponse struct {
 	Coordinates  Coordinates `json:"coordinates"`
 	Country      string      `json:"country"`
 	Language     string      `json:"language"`
+	Locale       string      `json:"locale,omitempty"`
 	Map          string      `json:"map"`
 	NearestPlace string      `json:"nearestPlace"`
 	Square       Square      `json:"square"`
@@ -70,3 +121,27 @@ type Coordinates struct {
 func (c Coordinates) ToString() string {
 	return fmt.Sprintf("%f,%f", c.Lat, c.Lng)
 }
+
+// Validate checks that the latitude is between -90 and 90 and the longitude between -180 and 180.
+// It returns a *ValidationError describing the first problem found, which is often swapped coordinates.
+func (c Coordinates) Validate() error {
+	return c.validate("coordinates")
+}
+
+func (c Coordinates) validate(field string) error {
+	if problem := c.problem(); problem != "" {
+		return &ValidationError{Field: field, Message: problem}
+	}
+	return nil
+}
+
+// problem describes what is wrong with the coordinates, or returns the empty string if they are valid.
+func (c Coordinates) problem() string {
+	switch {
+	case !(c.Lat >= -90 && c.Lat <= 90):
+		return fmt.Sprintf("latitude %g must be between -90 and 90", c.Lat)
+	case !(c.Lng >= -180 && c.Lng <= 180):
+		return fmt.Sprintf("longitude %g must be between -180 and 180", c.Lng)
+	}
+	return ""
+}
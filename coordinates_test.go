@@ -0,0 +1,163 @@
+package what3words
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// closedPolygon returns a closed polygon of n coordinates around a point in London.
+func closedPolygon(n int) PolygonCoordinates {
+	polygon := make(PolygonCoordinates, n)
+	for i := 0; i < n-1; i++ {
+		angle := 2 * math.Pi * float64(i) / float64(n-1)
+		polygon[i] = Coordinates{Lat: 51.52 + 0.01*math.Sin(angle), Lng: -0.19 + 0.01*math.Cos(angle)}
+	}
+	polygon[n-1] = polygon[0]
+	return polygon
+}
+
+func TestCoordinates_Validate(t *testing.T) {
+	tests := map[string]struct {
+		coordinates   Coordinates
+		expectedError string
+	}{
+		"valid":              {coordinates: Coordinates{Lat: 51.520847, Lng: -0.195521}},
+		"poles and meridian": {coordinates: Coordinates{Lat: -90, Lng: 180}},
+		"latitude too large": {
+			coordinates:   Coordinates{Lat: 91, Lng: 0},
+			expectedError: "invalid coordinates: latitude 91 must be between -90 and 90",
+		},
+		"swapped": {
+			coordinates:   Coordinates{Lat: -151.2093, Lng: -33.8688},
+			expectedError: "invalid coordinates: latitude -151.2093 must be between -90 and 90",
+		},
+		"longitude too large": {
+			coordinates:   Coordinates{Lat: 0, Lng: 180.5},
+			expectedError: "invalid coordinates: longitude 180.5 must be between -180 and 180",
+		},
+		"not a number": {
+			coordinates:   Coordinates{Lat: math.NaN(), Lng: 0},
+			expectedError: "invalid coordinates: latitude NaN must be between -90 and 90",
+		},
+		"infinite": {
+			coordinates:   Coordinates{Lat: 0, Lng: math.Inf(-1)},
+			expectedError: "invalid coordinates: longitude -Inf must be between -180 and 180",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assertValidationError(t, tt.expectedError, tt.coordinates.Validate())
+		})
+	}
+}
+
+func TestBoundingBox_Validate(t *testing.T) {
+	tests := map[string]struct {
+		box           *BoundingBox
+		expectedError string
+	}{
+		"valid":                   {box: NewBoundingBox(52.207988, 0.116126, 52.208867, 0.117540)},
+		"across the antimeridian": {box: NewBoundingBox(-16.8, 179.98, -16.79, -179.98)},
+		"single point":            {box: NewBoundingBox(51.5, -0.1, 51.5, -0.1)},
+		"south above north": {
+			box:           NewBoundingBox(52.208867, 0.116126, 52.207988, 0.117540),
+			expectedError: "invalid bounding-box: south latitude 52.208867 must not be greater than north latitude 52.207988",
+		},
+		"swapped corners": {
+			box:           NewBoundingBox(0.116126, 52.207988, 0.117540, 252.208867),
+			expectedError: "invalid bounding-box: longitude 252.208867 must be between -180 and 180",
+		},
+		"invalid south west": {
+			box:           NewBoundingBox(-91, 0, 0, 0),
+			expectedError: "invalid bounding-box: latitude -91 must be between -90 and 90",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assertValidationError(t, tt.expectedError, tt.box.Validate())
+		})
+	}
+}
+
+func TestCoordinateRadius_Validate(t *testing.T) {
+	tests := map[string]struct {
+		circle        CoordinateRadius
+		expectedError string
+	}{
+		"valid": {circle: CoordinateRadius{Coordinates: Coordinates{Lat: 51.52, Lng: -0.19}, Radius: 10}},
+		"zero radius": {
+			circle:        CoordinateRadius{Coordinates: Coordinates{Lat: 51.52, Lng: -0.19}},
+			expectedError: "invalid circle: radius 0 must be greater than 0",
+		},
+		"negative radius": {
+			circle:        CoordinateRadius{Coordinates: Coordinates{Lat: 51.52, Lng: -0.19}, Radius: -5},
+			expectedError: "invalid circle: radius -5 must be greater than 0",
+		},
+		"invalid centre": {
+			circle:        CoordinateRadius{Coordinates: Coordinates{Lat: 95, Lng: -0.19}, Radius: 10},
+			expectedError: "invalid circle: latitude 95 must be between -90 and 90",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assertValidationError(t, tt.expectedError, tt.circle.Validate())
+		})
+	}
+}
+
+func TestPolygonCoordinates_Validate(t *testing.T) {
+	open := closedPolygon(5)
+	open[4] = Coordinates{Lat: 51.5, Lng: -0.2}
+	invalidPoint := closedPolygon(5)
+	invalidPoint[2].Lng = 190
+
+	tests := map[string]struct {
+		polygon       PolygonCoordinates
+		expectedError string
+	}{
+		"triangle":       {polygon: closedPolygon(4)},
+		"25 coordinates": {polygon: closedPolygon(25)},
+		"26 coordinates": {
+			polygon:       closedPolygon(26),
+			expectedError: "invalid polygon: is limited to 25 coordinates, got 26",
+		},
+		"too few coordinates": {
+			polygon:       closedPolygon(3),
+			expectedError: "invalid polygon: must have at least 4 coordinates, got 3",
+		},
+		"empty": {
+			polygon:       PolygonCoordinates{},
+			expectedError: "invalid polygon: must have at least 4 coordinates, got 0",
+		},
+		"not closed": {
+			polygon:       open,
+			expectedError: "invalid polygon: must be closed, with the first coordinates repeated as the last",
+		},
+		"invalid coordinates": {
+			polygon:       invalidPoint,
+			expectedError: "invalid polygon: point 3: longitude 190 must be between -180 and 180",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assertValidationError(t, tt.expectedError, tt.polygon.Validate())
+		})
+	}
+}
+
+// assertValidationError asserts that err is nil if expectedError is empty, or otherwise a *ValidationError
+// with the expected message.
+func assertValidationError(t *testing.T, expectedError string, err error) {
+	t.Helper()
+
+	if expectedError == "" {
+		assert.NoError(t, err)
+		return
+	}
+
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.EqualError(t, err, expectedError)
+}
@@ -0,0 +1,86 @@
+package what3words
+
+import (
+	"context"
+	"net/url"
+	"sync"
+)
+
+// WithRequestDeduplication is a Functional Option for merging identical requests which are in flight at the same
+// time, so that concurrent callers looking up the same 3 word address share a single HTTP request.
+// Requests are identical when they are for the same endpoint with the same query parameters.
+// Each caller still returns as soon as its own context is done, and the shared request is only cancelled
+// once every caller waiting for it has gone.
+func WithRequestDeduplication() Option {
+	return func(w *w3w) {
+		w.flights = &flightGroup{calls: make(map[string]*flight)}
+	}
+}
+
+// flightGroup merges concurrent calls with the same key into a single call.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+// flight is a call in progress, shared by every caller with the same key.
+type flight struct {
+	done    chan struct{}
+	resp    response
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// flightKey returns the key identifying identical requests. url.Values.Encode sorts the parameters,
+// so the key does not depend on the order the parameters were set in.
+func flightKey(u *url.URL) string {
+	return u.Scheme + "://" + u.Host + u.Path + "?" + u.Query().Encode()
+}
+
+// do calls fn once for all concurrent callers with the same key and returns its result to each of them.
+// fn is called with a context which is only cancelled once every caller has returned.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (response, error)) (response, error) {
+	g.mu.Lock()
+	f, ok := g.calls[key]
+	if !ok {
+		flightCtx, cancel := context.WithCancel(context.Background())
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = f
+
+		go func() {
+			f.resp, f.err = fn(flightCtx)
+
+			g.mu.Lock()
+			g.forget(key, f)
+			g.mu.Unlock()
+
+			cancel()
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.resp, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Nobody is waiting for the result any more, so later callers must start a new request.
+			g.forget(key, f)
+			f.cancel()
+		}
+		g.mu.Unlock()
+		return response{}, ctx.Err()
+	}
+}
+
+// forget removes the flight for the key if it is still the current one. Must be called with g.mu held.
+func (g *flightGroup) forget(key string, f *flight) {
+	if g.calls[key] == f {
+		delete(g.calls, key)
+	}
+}
@@ -0,0 +1,139 @@
+package what3words
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waitForWaiters blocks until n callers are waiting on the flight for the key.
+func waitForWaiters(t *testing.T, g *flightGroup, key string, n int) {
+	t.Helper()
+	assert.Eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		f, ok := g.calls[key]
+		return ok && f.waiters == n
+	}, 5*time.Second, time.Millisecond)
+}
+
+func TestW3w_RequestDeduplication(t *testing.T) {
+	const callers = 50
+
+	var calls int32
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		_, err := rw.Write([]byte(`{
+			"country": "GB",
+			"coordinates": {"lng": -0.195521, "lat": 51.520847},
+			"words": "filled.count.soap",
+			"language": "en"
+		}`))
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	w := NewClient("example-api-key", WithEndpoint(u), WithRequestDeduplication())
+
+	var wg sync.WaitGroup
+	results := make([]*LocationResponse, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := w.ConvertToCoordinates(context.Background(), "filled.count.soap")
+			assert.NoError(t, err)
+			results[i] = resp
+		}(i)
+	}
+
+	expected := w.(*w3w).endpointURL("convert-to-coordinates")
+	expected.RawQuery = url.Values{"words": {"filled.count.soap"}, "language": {"en"}}.Encode()
+	waitForWaiters(t, w.(*w3w).flights, flightKey(expected), callers)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for _, resp := range results {
+		assert.Equal(t, "filled.count.soap", resp.Words)
+	}
+}
+
+func TestFlightGroup_Cancellation(t *testing.T) {
+	t.Run("cancelled caller returns while others receive the result", func(t *testing.T) {
+		g := &flightGroup{calls: make(map[string]*flight)}
+		release := make(chan struct{})
+		fn := func(ctx context.Context) (response, error) {
+			<-release
+			return response{body: []byte("result")}, nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelled := make(chan error)
+		go func() {
+			_, err := g.do(ctx, "key", fn)
+			cancelled <- err
+		}()
+
+		waitForWaiters(t, g, "key", 1)
+
+		var resp response
+		done := make(chan struct{})
+		go func() {
+			var err error
+			resp, err = g.do(context.Background(), "key", fn)
+			assert.NoError(t, err)
+			close(done)
+		}()
+
+		waitForWaiters(t, g, "key", 2)
+		cancel()
+		assert.ErrorIs(t, <-cancelled, context.Canceled)
+
+		close(release)
+		<-done
+		assert.Equal(t, []byte("result"), resp.body)
+	})
+
+	t.Run("shared request is cancelled once every caller has gone", func(t *testing.T) {
+		g := &flightGroup{calls: make(map[string]*flight)}
+		fnCancelled := make(chan struct{})
+		fn := func(ctx context.Context) (response, error) {
+			<-ctx.Done()
+			close(fnCancelled)
+			return response{}, ctx.Err()
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				_, err := g.do(ctx, "key", fn)
+				errs <- err
+			}()
+		}
+
+		waitForWaiters(t, g, "key", 2)
+		cancel()
+		assert.ErrorIs(t, <-errs, context.Canceled)
+		assert.ErrorIs(t, <-errs, context.Canceled)
+
+		select {
+		case <-fnCancelled:
+		case <-time.After(5 * time.Second):
+			t.Fatal("shared request was not cancelled")
+		}
+	})
+}
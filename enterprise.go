@@ -0,0 +1,104 @@
+package what3words
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrUnsupportedTransport is returned by every request of a client configured with WithClientCertificate or
+// WithRootCAs whose HTTP client has a transport other than *http.Transport, which cannot be configured for TLS.
+var ErrUnsupportedTransport = errors.New("client certificates and root CAs require an *http.Transport")
+
+// WithClientCertificate is a Functional Option for authenticating to a self-hosted Enterprise Suite deployment with
+// mutual TLS. The certificate is presented to the server on every request, e.g. one loaded with tls.LoadX509KeyPair.
+// The option configures a copy of the transport of the HTTP client, which must be an *http.Transport, or otherwise
+// every request fails with ErrUnsupportedTransport.
+func WithClientCertificate(certificate tls.Certificate) Option {
+	return func(w *w3w) {
+		w.certificates = append(w.certificates, certificate)
+	}
+}
+
+// WithRootCAs is a Functional Option for verifying the server certificate against a custom CA bundle instead of the
+// system roots, for example for a self-hosted Enterprise Suite deployment using an internal certificate authority.
+// The option configures a copy of the transport of the HTTP client, which must be an *http.Transport, or otherwise
+// every request fails with ErrUnsupportedTransport.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(w *w3w) {
+		w.rootCAs = pool
+	}
+}
+
+// WithHealthEndpoint is a Functional Option for setting the URL requested by Health, for self-hosted Enterprise Suite
+// deployments which expose a dedicated health endpoint. It may be outside the API endpoint, e.g. https://host/health.
+func WithHealthEndpoint(endpoint *url.URL) Option {
+	return func(w *w3w) {
+		w.health = endpoint
+	}
+}
+
+// configureTLS applies the client certificates and root CAs to a copy of the HTTP client and its transport.
+// It returns ErrUnsupportedTransport if the transport is not an *http.Transport.
+func (w *w3w) configureTLS() error {
+	if len(w.certificates) == 0 && w.rootCAs == nil {
+		return nil
+	}
+
+	base := w.http.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("configuring TLS: %w, got %T", ErrUnsupportedTransport, base)
+	}
+
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if len(w.certificates) > 0 {
+		transport.TLSClientConfig.Certificates = append(transport.TLSClientConfig.Certificates, w.certificates...)
+	}
+	if w.rootCAs != nil {
+		transport.TLSClientConfig.RootCAs = w.rootCAs
+	}
+
+	client := *w.http
+	client.Transport = transport
+	w.http = &client
+	return nil
+}
+
+// Health checks that the API can be reached and accepts the client's credentials, returning nil if it is healthy.
+// It requests the endpoint set with WithHealthEndpoint, or the available languages if none is set, and treats any
+// 2xx status as healthy. The request bypasses the cache, rate limiter and retries.
+func (w *w3w) Health(ctx context.Context, opts ...CallOption) error {
+	if w.configErr != nil {
+		return fmt.Errorf("checking health: %w", w.configErr)
+	}
+
+	c := w.newCall(opts)
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	u := w.health
+	if u == nil {
+		u = w.endpointURL("available-languages")
+	}
+
+	resp, err := w.sendWithFailover(ctx, u, c)
+	if err != nil {
+		return fmt.Errorf("checking health: %w", err)
+	}
+	if c.servedKey != nil {
+		*c.servedKey = resp.key
+	}
+
+	return nil
+}
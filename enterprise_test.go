@@ -0,0 +1,289 @@
+package what3words
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newClientCertificate creates a self-signed certificate for client authentication and a pool which trusts it.
+func newClientCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "w3w-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, pool
+}
+
+func TestW3w_EndpointURL(t *testing.T) {
+	tests := map[string]struct {
+		endpoint string
+		expected string
+	}{
+		"default endpoint":       {endpoint: "https://api.what3words.com/v3", expected: "https://api.what3words.com/v3/convert-to-3wa"},
+		"no path":                {endpoint: "http://w3w.internal:8080", expected: "http://w3w.internal:8080/convert-to-3wa"},
+		"path prefix":            {endpoint: "https://gateway.internal/what3words/v3", expected: "https://gateway.internal/what3words/v3/convert-to-3wa"},
+		"trailing slash":         {endpoint: "https://gateway.internal/what3words/v3/", expected: "https://gateway.internal/what3words/v3/convert-to-3wa"},
+		"escaped path":           {endpoint: "https://gateway.internal/tenant%2Fa/v3", expected: "https://gateway.internal/tenant%2Fa/v3/convert-to-3wa"},
+		"dot segments kept":      {endpoint: "https://gateway.internal/a/../v3", expected: "https://gateway.internal/a/../v3/convert-to-3wa"},
+		"query parameters kept":  {endpoint: "https://gateway.internal/v3?tenant=a", expected: "https://gateway.internal/v3/convert-to-3wa?tenant=a"},
+		"trailing slash no path": {endpoint: "https://gateway.internal/", expected: "https://gateway.internal/convert-to-3wa"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			endpoint, err := url.Parse(tt.endpoint)
+			assert.NoError(t, err)
+
+			w := NewClient("key", WithEndpoint(endpoint)).(*w3w)
+			assert.Equal(t, tt.expected, w.endpointURL("convert-to-3wa").String())
+		})
+	}
+}
+
+func TestW3w_EnterpriseSuite(t *testing.T) {
+	var (
+		paths   []string
+		queries []url.Values
+		keys    [][]string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		queries = append(queries, r.URL.Query())
+		keys = append(keys, r.Header.Values("X-Api-Key"))
+		_, _ = rw.Write([]byte(`{"words": "filled.count.soap", "language": "en"}`))
+	}))
+	defer ts.Close()
+
+	endpoint, err := url.Parse(ts.URL + "/what3words/v3?tenant=a")
+	assert.NoError(t, err)
+
+	w := NewClient("", WithEndpoint(endpoint))
+	_, err = w.ConvertTo3wa(context.Background(), &Coordinates{Lat: 51.520847, Lng: -0.195521})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"/what3words/v3/convert-to-3wa"}, paths)
+	assert.Equal(t, "a", queries[0].Get("tenant"))
+	assert.Equal(t, "51.520847,-0.195521", queries[0].Get("coordinates"))
+	assert.Empty(t, keys[0], "no X-Api-Key header is sent without a key")
+}
+
+func TestW3w_EnterpriseSuiteAutoSuggest(t *testing.T) {
+	var queries []url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		_, _ = rw.Write([]byte(`{"suggestions": []}`))
+	}))
+	defer ts.Close()
+
+	endpoint, err := url.Parse(ts.URL + "/what3words/v3?tenant=a")
+	assert.NoError(t, err)
+
+	tests := map[string]func(w What3Words) error{
+		"autosuggest": func(w What3Words) error {
+			_, err := w.AutoSuggest(context.Background(), &AutoSuggestInput{Words: "filled.count.so"})
+			return err
+		},
+		"autosuggest with coordinates": func(w What3Words) error {
+			_, err := w.AutoSuggestWithCoordinates(context.Background(), &AutoSuggestInput{Words: "filled.count.so"})
+			return err
+		},
+		"report selection": func(w What3Words) error {
+			return w.ReportSelection(context.Background(), &AutoSuggestInput{Words: "filled.count.so"}, Suggestion{Words: "filled.count.soap"}, 1)
+		},
+	}
+	for name, call := range tests {
+		t.Run(name, func(t *testing.T) {
+			queries = nil
+			assert.NoError(t, call(NewClient("", WithEndpoint(endpoint))))
+
+			assert.Len(t, queries, 1)
+			assert.Equal(t, "a", queries[0].Get("tenant"))
+			assert.Contains(t, []string{queries[0].Get("input"), queries[0].Get("raw-input")}, "filled.count.so")
+		})
+	}
+}
+
+func TestW3w_MutualTLS(t *testing.T) {
+	certificate, clientCAs := newClientCertificate(t)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		_, _ = rw.Write([]byte(`{"languages": [{"code": "en", "name": "English", "nativeName": "English"}]}`))
+	}))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs, MinVersion: tls.VersionTLS12}
+	ts.StartTLS()
+	defer ts.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ts.Certificate())
+	endpoint, err := url.Parse(ts.URL + "/v3")
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		opts          []Option
+		expectedError bool
+	}{
+		"client certificate and CA bundle": {
+			opts: []Option{WithClientCertificate(certificate), WithRootCAs(rootCAs)},
+		},
+		"custom HTTP client": {
+			opts: []Option{
+				WithHTTPClient(&http.Client{Timeout: 5 * time.Second, Transport: &http.Transport{}}),
+				WithClientCertificate(certificate),
+				WithRootCAs(rootCAs),
+			},
+		},
+		"missing client certificate": {
+			opts:          []Option{WithRootCAs(rootCAs)},
+			expectedError: true,
+		},
+		"missing CA bundle": {
+			opts:          []Option{WithClientCertificate(certificate)},
+			expectedError: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := NewClient("", append([]Option{WithEndpoint(endpoint)}, tt.opts...)...)
+
+			languages, err := w.AvailableLanguages(context.Background())
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []Language{{Code: "en", Name: "English", NativeName: "English"}}, languages)
+		})
+	}
+
+	if config := http.DefaultTransport.(*http.Transport).TLSClientConfig; config != nil {
+		assert.Nil(t, config.RootCAs, "the default transport is not modified")
+		assert.Empty(t, config.Certificates, "the default transport is not modified")
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestW3w_MutualTLSUnsupportedTransport(t *testing.T) {
+	certificate, rootCAs := newClientCertificate(t)
+
+	tests := map[string][]Option{
+		"client certificate": {WithClientCertificate(certificate)},
+		"CA bundle":          {WithRootCAs(rootCAs)},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls int
+			transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				calls++
+				return nil, errors.New("unexpected request")
+			})
+			w := NewClient("", append([]Option{WithHTTPClient(&http.Client{Transport: transport})}, opts...)...)
+
+			_, err := w.AvailableLanguages(context.Background())
+			assert.ErrorIs(t, err, ErrUnsupportedTransport)
+			assert.ErrorIs(t, w.Health(context.Background()), ErrUnsupportedTransport)
+			assert.Zero(t, calls, "no request is sent without the TLS settings")
+		})
+	}
+}
+
+func TestW3w_Health(t *testing.T) {
+	tests := map[string]struct {
+		healthPath    string
+		status        int
+		expectedPath  string
+		expectedError error
+	}{
+		"healthy using available languages": {
+			status:       http.StatusOK,
+			expectedPath: "/v3/available-languages",
+		},
+		"healthy using health endpoint": {
+			healthPath:   "/health",
+			status:       http.StatusNoContent,
+			expectedPath: "/health",
+		},
+		"unhealthy": {
+			healthPath:    "/health",
+			status:        http.StatusServiceUnavailable,
+			expectedPath:  "/health",
+			expectedError: &APIError{StatusCode: http.StatusServiceUnavailable, Endpoint: "health"},
+		},
+		"invalid key": {
+			status:        http.StatusUnauthorized,
+			expectedPath:  "/v3/available-languages",
+			expectedError: ErrInvalidKey,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var path string
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				rw.WriteHeader(tt.status)
+				if tt.status == http.StatusUnauthorized {
+					_, _ = rw.Write([]byte(`{"error": {"code": "InvalidKey", "message": "Authentication failed; invalid API key"}}`))
+				}
+			}))
+			defer ts.Close()
+
+			endpoint, _ := url.Parse(ts.URL + "/v3")
+			opts := []Option{WithEndpoint(endpoint)}
+			if tt.healthPath != "" {
+				health, _ := url.Parse(ts.URL + tt.healthPath)
+				opts = append(opts, WithHealthEndpoint(health))
+			}
+
+			err := NewClient("key", opts...).Health(context.Background())
+			assert.Equal(t, tt.expectedPath, path)
+
+			var apiErr *APIError
+			switch expected := tt.expectedError; {
+			case expected == nil:
+				assert.NoError(t, err)
+			case errors.As(expected, &apiErr):
+				var got *APIError
+				if assert.ErrorAs(t, err, &got) {
+					assert.Equal(t, apiErr, got)
+				}
+			default:
+				assert.ErrorIs(t, err, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package what3words
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrorCode is an error code returned in the body of a failed what3words API response.
+// ErrorCode implements the error interface, so the documented codes can be used directly as sentinel
+// errors with errors.Is, for example errors.Is(err, what3words.ErrBadWords).
+type ErrorCode string
+
+// Error returns the error code as a string.
+func (c ErrorCode) Error() string {
+	return string(c)
+}
+
+// Documented what3words API error codes.
+const (
+	// Authentication and quota errors.
+	ErrMissingKey        ErrorCode = "MissingKey"
+	ErrInvalidKey        ErrorCode = "InvalidKey"
+	ErrSuspendedKey      ErrorCode = "SuspendedKey"
+	ErrInvalidReferer    ErrorCode = "InvalidReferer"
+	ErrInvalidAPIVersion ErrorCode = "InvalidApiVersion"
+	ErrQuotaExceeded     ErrorCode = "QuotaExceeded"
+
+	// Request parameter errors.
+	ErrBadWords             ErrorCode = "BadWords"
+	ErrMissingWords         ErrorCode = "MissingWords"
+	ErrBadCoordinates       ErrorCode = "BadCoordinates"
+	ErrMissingCoordinates   ErrorCode = "MissingCoordinates"
+	ErrMissingLanguage      ErrorCode = "MissingLanguage"
+	ErrBadLanguage          ErrorCode = "BadLanguage"
+	ErrBadLocale            ErrorCode = "BadLocale"
+	ErrBadFormat            ErrorCode = "BadFormat"
+	ErrBadInput             ErrorCode = "BadInput"
+	ErrMissingInput         ErrorCode = "MissingInput"
+	ErrBadInputType         ErrorCode = "BadInputType"
+	ErrBadFocus             ErrorCode = "BadFocus"
+	ErrBadNResults          ErrorCode = "BadNResults"
+	ErrBadNFocusResults     ErrorCode = "BadNFocusResults"
+	ErrBadClipToCountry     ErrorCode = "BadClipToCountry"
+	ErrBadClipToCircle      ErrorCode = "BadClipToCircle"
+	ErrBadClipToBoundingBox ErrorCode = "BadClipToBoundingBox"
+	ErrBadClipToPolygon     ErrorCode = "BadClipToPolygon"
+	ErrBadPreferLand        ErrorCode = "BadPreferLand"
+	ErrBadBoundingBox       ErrorCode = "BadBoundingBox"
+	ErrBadBoundingBoxTooBig ErrorCode = "BadBoundingBoxTooBig"
+	ErrMissingBoundingBox   ErrorCode = "MissingBoundingBox"
+	ErrBadRawInput          ErrorCode = "BadRawInput"
+	ErrBadSelection         ErrorCode = "BadSelection"
+	ErrBadRank              ErrorCode = "BadRank"
+	ErrBadSourceAPI         ErrorCode = "BadSourceApi"
+
+	// Server errors.
+	ErrInternalServerError ErrorCode = "InternalServerError"
+)
+
+// clientErrorCodes contains the documented error codes which are caused by the request,
+// so repeating the same request will never succeed.
+var clientErrorCodes = map[ErrorCode]bool{
+	ErrMissingKey:           true,
+	ErrInvalidKey:           true,
+	ErrSuspendedKey:         true,
+	ErrInvalidReferer:       true,
+	ErrInvalidAPIVersion:    true,
+	ErrQuotaExceeded:        true,
+	ErrBadWords:             true,
+	ErrMissingWords:         true,
+	ErrBadCoordinates:       true,
+	ErrMissingCoordinates:   true,
+	ErrMissingLanguage:      true,
+	ErrBadLanguage:          true,
+	ErrBadLocale:            true,
+	ErrBadFormat:            true,
+	ErrBadInput:             true,
+	ErrMissingInput:         true,
+	ErrBadInputType:         true,
+	ErrBadFocus:             true,
+	ErrBadNResults:          true,
+	ErrBadNFocusResults:     true,
+	ErrBadClipToCountry:     true,
+	ErrBadClipToCircle:      true,
+	ErrBadClipToBoundingBox: true,
+	ErrBadClipToPolygon:     true,
+	ErrBadPreferLand:        true,
+	ErrBadBoundingBox:       true,
+	ErrBadBoundingBoxTooBig: true,
+	ErrMissingBoundingBox:   true,
+	ErrBadRawInput:          true,
+	ErrBadSelection:         true,
+	ErrBadRank:              true,
+	ErrBadSourceAPI:         true,
+}
+
+// APIError is returned when the what3words API responds with a non 200 status.
+// It contains the HTTP status, the endpoint that was called and the error code and message decoded from the
+// response body. APIError unwraps to its ErrorCode, so it can be matched with errors.Is against the Err constants
+// or extracted with errors.As.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Code is the what3words error code, e.g. BadWords. It is empty if the response body could not be decoded.
+	Code ErrorCode
+
+	// Message is the human readable error message returned by the API.
+	Message string
+
+	// Endpoint is the API endpoint that was called, e.g. convert-to-3wa.
+	Endpoint string
+
+	// RetryAfter is the delay requested by the Retry-After response header, or zero if it was not set.
+	RetryAfter time.Duration
+}
+
+// apiErrorResponse is the JSON body returned by the what3words API for failed requests.
+type apiErrorResponse struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Error returns a description of the failed request.
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("%s returned unexpected status %d %s", e.Endpoint, e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("%s returned status %d: %s: %s", e.Endpoint, e.StatusCode, e.Code, e.Message)
+}
+
+// Unwrap returns the ErrorCode of the APIError, allowing errors.Is to match on the documented error codes.
+func (e *APIError) Unwrap() error {
+	if e.Code == "" {
+		return nil
+	}
+	return e.Code
+}
+
+// Temporary reports whether the request may succeed if retried, i.e. the API responded with
+// 429 Too Many Requests or a 5xx status and the error code is not a documented client error.
+func (e *APIError) Temporary() bool {
+	if clientErrorCodes[e.Code] {
+		return false
+	}
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// ValidationError is returned when a request parameter fails client-side validation,
+// before any request is sent to the what3words API.
+type ValidationError struct {
+	// Field is the name of the invalid API parameter, e.g. n-results.
+	Field string
+
+	// Message describes why the parameter is invalid.
+	Message string
+}
+
+// Error returns a description of the invalid parameter.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Message)
+}
@@ -0,0 +1,223 @@
+package what3words
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// GeoJSON object and geometry types.
+const (
+	GeoJSONFeature           = "Feature"
+	GeoJSONFeatureCollection = "FeatureCollection"
+	GeoJSONPoint             = "Point"
+	GeoJSONPolygon           = "Polygon"
+	GeoJSONMultiLineString   = "MultiLineString"
+)
+
+// Position is a GeoJSON position. As defined by RFC 7946 the longitude comes first, followed by the latitude.
+type Position [2]float64
+
+// Geometry is a GeoJSON Polygon or MultiLineString geometry.
+// For a Polygon, Coordinates contains the linear rings of the polygon, the first being the exterior ring.
+// For a MultiLineString, Coordinates contains the positions of each line.
+type Geometry struct {
+	Type        string       `json:"type"`
+	Coordinates [][]Position `json:"coordinates"`
+}
+
+// Feature is a GeoJSON Feature.
+type Feature struct {
+	Type       string                 `json:"type"`
+	BBox       []float64              `json:"bbox,omitempty"`
+	Geometry   Geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// FeatureCollection is a GeoJSON FeatureCollection.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// NewFeatureCollection constructs a FeatureCollection containing the given features.
+func NewFeatureCollection(features ...Feature) *FeatureCollection {
+	if features == nil {
+		features = []Feature{}
+	}
+	return &FeatureCollection{
+		Type:     GeoJSONFeatureCollection,
+		Features: features,
+	}
+}
+
+// geoJSONDecoder is implemented by the responses of endpoints supporting FormatGeoJSON.
+type geoJSONDecoder interface {
+	decodeGeoJSON(body []byte) error
+}
+
+// geoJSONResponse is a response of the API in the geojson format, which is a FeatureCollection containing a single
+// Feature. The coordinates are decoded according to the type of the geometry.
+type geoJSONResponse struct {
+	Features []struct {
+		BBox     []float64 `json:"bbox"`
+		Geometry struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+		Properties json.RawMessage `json:"properties"`
+	} `json:"features"`
+}
+
+// decodeGeoJSONFeature decodes the single Feature of a response in the geojson format, checking it has the given
+// geometry type, and returns its bbox, coordinates and properties.
+func decodeGeoJSONFeature(body []byte, geometry string) ([]float64, json.RawMessage, json.RawMessage, error) {
+	var resp geoJSONResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, nil, nil, err
+	}
+	if len(resp.Features) != 1 {
+		return nil, nil, nil, fmt.Errorf("expected 1 feature, got %d", len(resp.Features))
+	}
+
+	feature := resp.Features[0]
+	if feature.Geometry.Type != geometry {
+		return nil, nil, nil, fmt.Errorf("expected %s geometry, got %q", geometry, feature.Geometry.Type)
+	}
+	return feature.BBox, feature.Geometry.Coordinates, feature.Properties, nil
+}
+
+// decodeGeoJSON decodes a location in the geojson format, a Point at the coordinates with the grid square as its bbox
+// and the other fields as properties.
+func (r *LocationResponse) decodeGeoJSON(body []byte) error {
+	bbox, coordinates, properties, err := decodeGeoJSONFeature(body, GeoJSONPoint)
+	if err != nil {
+		return err
+	}
+	if len(bbox) != 4 {
+		return fmt.Errorf("expected bbox of 4 values, got %d", len(bbox))
+	}
+
+	var position Position
+	if err := json.Unmarshal(coordinates, &position); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(properties, r); err != nil {
+		return err
+	}
+
+	r.Coordinates = Coordinates{Lat: position[1], Lng: position[0]}
+	r.Square = Square{
+		Southwest: Coordinates{Lat: bbox[1], Lng: bbox[0]},
+		Northeast: Coordinates{Lat: bbox[3], Lng: bbox[2]},
+	}
+	return nil
+}
+
+// decodeGeoJSON decodes a grid section in the geojson format, a MultiLineString of the lines.
+func (g *GridSection) decodeGeoJSON(body []byte) error {
+	_, coordinates, _, err := decodeGeoJSONFeature(body, GeoJSONMultiLineString)
+	if err != nil {
+		return err
+	}
+
+	var lines [][]Position
+	if err := json.Unmarshal(coordinates, &lines); err != nil {
+		return err
+	}
+
+	g.Lines = make([]GridLine, 0, len(lines))
+	for _, line := range lines {
+		if len(line) != 2 {
+			return fmt.Errorf("expected lines of 2 positions, got %d", len(line))
+		}
+		g.Lines = append(g.Lines, GridLine{
+			Start: Coordinates{Lat: line[0][1], Lng: line[0][0]},
+			End:   Coordinates{Lat: line[1][1], Lng: line[1][0]},
+		})
+	}
+	return nil
+}
+
+// Position returns the coordinates as a GeoJSON position.
+func (c Coordinates) Position() Position {
+	return Position{c.Lng, c.Lat}
+}
+
+// Feature returns the grid square of the LocationResponse as a GeoJSON Polygon Feature,
+// with the words, country, nearestPlace, language and map as properties. Unlike the API's geojson format,
+// which only describes the square as the bbox of a Point, the Polygon can be loaded directly into map and GIS tools.
+func (r LocationResponse) Feature() Feature {
+	sw, ne := r.Square.Southwest, r.Square.Northeast
+	return Feature{
+		Type: GeoJSONFeature,
+		BBox: []float64{sw.Lng, sw.Lat, ne.Lng, ne.Lat},
+		Geometry: Geometry{
+			Type: GeoJSONPolygon,
+			Coordinates: [][]Position{{
+				sw.Position(),
+				{ne.Lng, sw.Lat},
+				ne.Position(),
+				{sw.Lng, ne.Lat},
+				sw.Position(),
+			}},
+		},
+		Properties: map[string]interface{}{
+			"words":        r.Words,
+			"country":      r.Country,
+			"nearestPlace": r.NearestPlace,
+			"language":     r.Language,
+			"map":          r.Map,
+		},
+	}
+}
+
+// Feature returns the lines of the GridSection as a GeoJSON MultiLineString Feature.
+func (g GridSection) Feature() Feature {
+	lines := make([][]Position, 0, len(g.Lines))
+	for _, line := range g.Lines {
+		lines = append(lines, []Position{line.Start.Position(), line.End.Position()})
+	}
+
+	return Feature{
+		Type: GeoJSONFeature,
+		Geometry: Geometry{
+			Type:        GeoJSONMultiLineString,
+			Coordinates: lines,
+		},
+		Properties: map[string]interface{}{},
+	}
+}
+
+// ConvertTo3waGeoJSON converts a latitude and longitude to a 3 word address, returning the grid square
+// as a GeoJSON FeatureCollection containing a single Polygon Feature.
+func (w *w3w) ConvertTo3waGeoJSON(ctx context.Context, coordinates *Coordinates, opts ...CallOption) (*FeatureCollection, error) {
+	resp, err := w.ConvertTo3wa(ctx, coordinates, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("converting coordinates to GeoJSON: %w", err)
+	}
+
+	return NewFeatureCollection(resp.Feature()), nil
+}
+
+// ConvertToCoordinatesGeoJSON converts a 3 word address to a latitude and longitude, returning the grid square
+// as a GeoJSON FeatureCollection containing a single Polygon Feature.
+func (w *w3w) ConvertToCoordinatesGeoJSON(ctx context.Context, words string, opts ...CallOption) (*FeatureCollection, error) {
+	resp, err := w.ConvertToCoordinates(ctx, words, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("converting w3w to GeoJSON: %w", err)
+	}
+
+	return NewFeatureCollection(resp.Feature()), nil
+}
+
+// GridSectionGeoJSON returns a section of the What3Words 3m x 3m grid as a GeoJSON FeatureCollection
+// containing a single MultiLineString Feature.
+func (w *w3w) GridSectionGeoJSON(ctx context.Context, box *BoundingBox, opts ...CallOption) (*FeatureCollection, error) {
+	resp, err := w.GridSection(ctx, box, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving grid section GeoJSON: %w", err)
+	}
+
+	return NewFeatureCollection(resp.Feature()), nil
+}
@@ -0,0 +1,121 @@
+package what3words
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeoJSON_Feature(t *testing.T) {
+	tests := map[string]struct {
+		feature  Feature
+		expected string
+	}{
+		"location response as polygon": {
+			feature: LocationResponse{
+				Country:      "GB",
+				Language:     "en",
+				Map:          "https://w3w.co/filled.count.soap",
+				NearestPlace: "Bayswater, London",
+				Square: Square{
+					Southwest: Coordinates{Lat: 51.520833, Lng: -0.195543},
+					Northeast: Coordinates{Lat: 51.52086, Lng: -0.195499},
+				},
+				Words: "filled.count.soap",
+			}.Feature(),
+			expected: `{
+				"type": "Feature",
+				"bbox": [-0.195543, 51.520833, -0.195499, 51.52086],
+				"geometry": {
+					"type": "Polygon",
+					"coordinates": [[
+						[-0.195543, 51.520833],
+						[-0.195499, 51.520833],
+						[-0.195499, 51.52086],
+						[-0.195543, 51.52086],
+						[-0.195543, 51.520833]
+					]]
+				},
+				"properties": {
+					"country": "GB",
+					"language": "en",
+					"map": "https://w3w.co/filled.count.soap",
+					"nearestPlace": "Bayswater, London",
+					"words": "filled.count.soap"
+				}
+			}`,
+		},
+		"grid section as multi line string": {
+			feature: GridSection{
+				Lines: []GridLine{
+					{
+						Start: Coordinates{Lat: 52.20801, Lng: 0.116126},
+						End:   Coordinates{Lat: 52.20801, Lng: 0.11754},
+					},
+					{
+						Start: Coordinates{Lat: 52.207988, Lng: 0.116136},
+						End:   Coordinates{Lat: 52.208867, Lng: 0.116136},
+					},
+				},
+			}.Feature(),
+			expected: `{
+				"type": "Feature",
+				"geometry": {
+					"type": "MultiLineString",
+					"coordinates": [
+						[[0.116126, 52.20801], [0.11754, 52.20801]],
+						[[0.116136, 52.207988], [0.116136, 52.208867]]
+					]
+				},
+				"properties": {}
+			}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := json.Marshal(tt.feature)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(got))
+
+			var roundTrip Feature
+			assert.NoError(t, json.Unmarshal(got, &roundTrip))
+			assert.Equal(t, tt.feature, roundTrip)
+		})
+	}
+}
+
+func TestW3w_ConvertTo3waGeoJSON(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/convert-to-3wa", r.URL.Path)
+		_, err := rw.Write([]byte(`{
+			"country": "GB",
+			"square": {
+				"southwest": {"lng": -0.195543, "lat": 51.520833},
+				"northeast": {"lng": -0.195499, "lat": 51.52086}
+			},
+			"nearestPlace": "Bayswater, London",
+			"coordinates": {"lng": -0.195521, "lat": 51.520847},
+			"words": "filled.count.soap",
+			"language": "en",
+			"map": "https://w3w.co/filled.count.soap"
+		}`))
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	w := NewClient("example-api-key", WithEndpoint(u))
+	got, err := w.ConvertTo3waGeoJSON(context.Background(), &Coordinates{Lat: 51.520847, Lng: -0.195521})
+	assert.NoError(t, err)
+	assert.Equal(t, GeoJSONFeatureCollection, got.Type)
+	assert.Len(t, got.Features, 1)
+	assert.Equal(t, GeoJSONPolygon, got.Features[0].Geometry.Type)
+	assert.Equal(t, "filled.count.soap", got.Features[0].Properties["words"])
+}
@@ -0,0 +1,310 @@
+package what3words
+
+import "math"
+
+// _earthRadius is the mean radius of the Earth in metres. Distances and areas treat the Earth as a sphere of this
+// radius, which is accurate to within about 0.5%.
+const _earthRadius = 6371008.8
+
+// DistanceTo returns the great circle distance in metres between the coordinates and other, using the haversine formula.
+func (c Coordinates) DistanceTo(other Coordinates) float64 {
+	lat1, lat2 := radians(c.Lat), radians(other.Lat)
+	dLat := lat2 - lat1
+	dLng := radians(other.Lng - c.Lng)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * _earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// BearingTo returns the initial bearing in degrees clockwise from north, from 0 up to but not including 360, of the
+// great circle from the coordinates to other. The bearing from a point to itself is 0.
+func (c Coordinates) BearingTo(other Coordinates) float64 {
+	lat1, lat2 := radians(c.Lat), radians(other.Lat)
+	dLng := radians(other.Lng - c.Lng)
+
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// Destination returns the coordinates reached by travelling distance metres along a great circle from the coordinates,
+// starting at bearing degrees clockwise from north. The longitude of the result is normalised.
+func (c Coordinates) Destination(bearing, distance float64) Coordinates {
+	lat1, lng1 := radians(c.Lat), radians(c.Lng)
+	theta := radians(bearing)
+	delta := distance / _earthRadius
+
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta))
+	lng2 := lng1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*math.Sin(lat2))
+
+	return Coordinates{Lat: degrees(lat2), Lng: degrees(lng2)}.NormaliseLongitude()
+}
+
+// NormaliseLongitude returns the coordinates with the longitude wrapped into the range -180 to 180, so that, for
+// example, a longitude of 190 becomes -170. Longitudes already in range, including 180, are returned unchanged.
+// The latitude is not changed.
+func (c Coordinates) NormaliseLongitude() Coordinates {
+	c.Lng = normaliseLng(c.Lng)
+	return c
+}
+
+// Centre returns the coordinates of the centre of the square.
+func (s Square) Centre() Coordinates {
+	return Coordinates{
+		Lat: (s.Southwest.Lat + s.Northeast.Lat) / 2,
+		Lng: (s.Southwest.Lng + s.Northeast.Lng) / 2,
+	}
+}
+
+// Contains reports whether the coordinates are inside the square. Squares include their southern and western edges,
+// but not their northern and eastern edges, so that a point on an edge shared by two squares belongs to exactly one.
+func (s Square) Contains(c Coordinates) bool {
+	return c.Lat >= s.Southwest.Lat && c.Lat < s.Northeast.Lat &&
+		c.Lng >= s.Southwest.Lng && c.Lng < s.Northeast.Lng
+}
+
+// Neighbours returns the squares surrounding the square, in the order north, north east, east, south east, south,
+// south west, west and north west. The neighbours are assumed to be the same size as the square, which holds for
+// squares in the same band of the grid but only approximately across bands, so use ConvertTo3wa with the Centre of
+// a neighbour to find the exact square. Neighbours beyond the poles are omitted, and longitudes wrap at the
+// antimeridian.
+func (s Square) Neighbours() []Square {
+	height := s.Northeast.Lat - s.Southwest.Lat
+	width := s.Northeast.Lng - s.Southwest.Lng
+
+	offsets := [8][2]float64{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+	neighbours := make([]Square, 0, len(offsets))
+	for _, offset := range offsets {
+		n := Square{
+			Southwest: Coordinates{Lat: s.Southwest.Lat + offset[0]*height, Lng: s.Southwest.Lng + offset[1]*width},
+			Northeast: Coordinates{Lat: s.Northeast.Lat + offset[0]*height, Lng: s.Northeast.Lng + offset[1]*width},
+		}
+		if n.Southwest.Lat < -90-_squareTolerance || n.Northeast.Lat > 90+_squareTolerance {
+			continue
+		}
+
+		switch {
+		case n.Southwest.Lng >= 180:
+			n.Southwest.Lng -= 360
+			n.Northeast.Lng -= 360
+		case n.Northeast.Lng <= -180:
+			n.Southwest.Lng += 360
+			n.Northeast.Lng += 360
+		}
+		neighbours = append(neighbours, n)
+	}
+	return neighbours
+}
+
+// NewBoundingBoxFromCentre constructs the smallest BoundingBox containing every point within radius metres of the
+// centre. Boxes which would reach a pole span every longitude.
+func NewBoundingBoxFromCentre(centre Coordinates, radius float64) *BoundingBox {
+	box := BoundingBox{SouthLat: centre.Lat, WestLng: centre.Lng, NorthLat: centre.Lat, EastLng: centre.Lng}.Expand(radius)
+	return &box
+}
+
+// Contains reports whether the coordinates are inside the box or on its edges. Boxes whose western longitude is
+// greater than their eastern longitude cross the antimeridian.
+func (b BoundingBox) Contains(c Coordinates) bool {
+	if c.Lat < b.SouthLat || c.Lat > b.NorthLat {
+		return false
+	}
+	return arcContains(b.WestLng, b.EastLng, normaliseLng(c.Lng))
+}
+
+// Intersects reports whether the box and other overlap or touch. Either box may cross the antimeridian.
+func (b BoundingBox) Intersects(other BoundingBox) bool {
+	if b.SouthLat > other.NorthLat || other.SouthLat > b.NorthLat {
+		return false
+	}
+	return arcContains(b.WestLng, b.EastLng, other.WestLng) || arcContains(other.WestLng, other.EastLng, b.WestLng)
+}
+
+// Union returns the smallest box containing both the box and other. Of the two ways round the globe to join their
+// longitudes, the narrower is used, so the union of boxes either side of the antimeridian crosses it.
+func (b BoundingBox) Union(other BoundingBox) BoundingBox {
+	union := BoundingBox{
+		SouthLat: math.Min(b.SouthLat, other.SouthLat),
+		NorthLat: math.Max(b.NorthLat, other.NorthLat),
+		WestLng:  -180,
+		EastLng:  180,
+	}
+
+	width := 360.0
+	for _, arc := range [][2]float64{
+		{b.WestLng, b.EastLng},
+		{other.WestLng, other.EastLng},
+		{b.WestLng, other.EastLng},
+		{other.WestLng, b.EastLng},
+	} {
+		if w := arcWidth(arc[0], arc[1]); w < width &&
+			arcCovers(arc[0], arc[1], b.WestLng, b.EastLng) && arcCovers(arc[0], arc[1], other.WestLng, other.EastLng) {
+			union.WestLng, union.EastLng, width = arc[0], arc[1], w
+		}
+	}
+	return union
+}
+
+// Expand returns the smallest box containing every point within metres of the box. Latitudes stop at the poles, and
+// boxes which would reach a pole or wrap around the globe span every longitude. Longitudes wrap at the antimeridian,
+// so the expanded box may cross it.
+func (b BoundingBox) Expand(metres float64) BoundingBox {
+	delta := metres / _earthRadius
+	expanded := BoundingBox{
+		SouthLat: math.Max(-90, b.SouthLat-degrees(delta)),
+		NorthLat: math.Min(90, b.NorthLat+degrees(delta)),
+		WestLng:  -180,
+		EastLng:  180,
+	}
+	if expanded.SouthLat == -90 || expanded.NorthLat == 90 {
+		return expanded
+	}
+
+	// Points are furthest in longitude from the edge nearest a pole. The longitude within the distance of such a point
+	// is furthest from it at the latitude where the great circle through them meets the meridian at a right angle.
+	furthest := radians(math.Max(math.Abs(b.SouthLat), math.Abs(b.NorthLat)))
+	if math.Sin(delta) >= math.Cos(furthest) {
+		return expanded
+	}
+	dLng := degrees(math.Asin(math.Sin(delta) / math.Cos(furthest)))
+	if arcWidth(b.WestLng, b.EastLng)+2*dLng >= 360 {
+		return expanded
+	}
+
+	expanded.WestLng = normaliseLng(b.WestLng - dLng)
+	expanded.EastLng = normaliseLng(b.EastLng + dLng)
+	return expanded
+}
+
+// Area returns the area of the box in square metres.
+func (b BoundingBox) Area() float64 {
+	return _earthRadius * _earthRadius * math.Abs(math.Sin(radians(b.NorthLat))-math.Sin(radians(b.SouthLat))) *
+		radians(arcWidth(b.WestLng, b.EastLng))
+}
+
+// Contains reports whether the coordinates are inside the polygon, using the even-odd rule. The polygon does not need
+// to be closed. Points exactly on an edge may be reported as either inside or outside.
+func (p PolygonCoordinates) Contains(c Coordinates) bool {
+	inside := false
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		a, b := p[i], p[j]
+		if (a.Lat > c.Lat) != (b.Lat > c.Lat) &&
+			c.Lng < (b.Lng-a.Lng)*(c.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Area returns the area in square metres enclosed by the polygon on the surface of the Earth, whichever direction its
+// points are in. Edges are taken to be straight on an equal area projection, so edges along lines of latitude are
+// exact. The polygon does not need to be closed, and may cross the antimeridian, but not enclose a pole.
+func (p PolygonCoordinates) Area() float64 {
+	ring := p.ring()
+	if len(ring) < 3 {
+		return 0
+	}
+
+	sum := 0.0
+	for i := range ring {
+		prev, next := ring[(i+len(ring)-1)%len(ring)], ring[(i+1)%len(ring)]
+		sum += radians(normaliseLng(next.Lng-prev.Lng)) * math.Sin(radians(ring[i].Lat))
+	}
+	return math.Abs(sum) * _earthRadius * _earthRadius / 2
+}
+
+// Centroid returns the centre of mass of the area enclosed by the polygon, treating latitude and longitude as flat,
+// which is accurate for polygons of up to a few hundred kilometres. The polygon does not need to be closed, and may
+// cross the antimeridian. The average of the points is returned for polygons which enclose no area, and the zero
+// Coordinates for an empty polygon.
+func (p PolygonCoordinates) Centroid() Coordinates {
+	ring := p.ring()
+	if len(ring) == 0 {
+		return Coordinates{}
+	}
+
+	// Longitudes are taken relative to the first point, so that polygons crossing the antimeridian are contiguous.
+	origin := ring[0]
+	points := make([]Coordinates, len(ring))
+	for i, c := range ring {
+		points[i] = Coordinates{Lat: c.Lat - origin.Lat, Lng: normaliseLng(c.Lng - origin.Lng)}
+	}
+
+	var area, lat, lng float64
+	for i, a := range points {
+		b := points[(i+1)%len(points)]
+		cross := a.Lng*b.Lat - b.Lng*a.Lat
+		area += cross
+		lat += (a.Lat + b.Lat) * cross
+		lng += (a.Lng + b.Lng) * cross
+	}
+
+	var centroid Coordinates
+	if math.Abs(area) < 1e-18 {
+		for _, c := range points {
+			centroid.Lat += c.Lat / float64(len(points))
+			centroid.Lng += c.Lng / float64(len(points))
+		}
+	} else {
+		centroid = Coordinates{Lat: lat / (3 * area), Lng: lng / (3 * area)}
+	}
+
+	return Coordinates{Lat: origin.Lat + centroid.Lat, Lng: origin.Lng + centroid.Lng}.NormaliseLongitude()
+}
+
+// ring returns the points of the polygon without the closing point, if the last point repeats the first.
+func (p PolygonCoordinates) ring() PolygonCoordinates {
+	if len(p) > 1 && p[0] == p[len(p)-1] {
+		return p[:len(p)-1]
+	}
+	return p
+}
+
+// normaliseLng wraps a longitude into the range -180 to 180, leaving longitudes already in range unchanged.
+func normaliseLng(lng float64) float64 {
+	if lng >= -180 && lng <= 180 {
+		return lng
+	}
+	lng = math.Mod(lng+180, 360)
+	if lng < 0 {
+		lng += 360
+	}
+	return lng - 180
+}
+
+// arcWidth returns the width in degrees of the longitudes from west eastwards to east, which crosses the
+// antimeridian if west is greater than east.
+func arcWidth(west, east float64) float64 {
+	if west > east {
+		return east - west + 360
+	}
+	return east - west
+}
+
+// arcContains reports whether the longitude is between west and east, travelling eastwards from west.
+func arcContains(west, east, lng float64) bool {
+	if west > east {
+		return lng >= west || lng <= east
+	}
+	return lng >= west && lng <= east
+}
+
+// arcCovers reports whether the longitudes from west to east include every longitude from innerWest to innerEast.
+func arcCovers(west, east, innerWest, innerEast float64) bool {
+	if !arcContains(west, east, innerWest) {
+		return false
+	}
+	offset := innerWest - west
+	if offset < 0 {
+		offset += 360
+	}
+	return offset+arcWidth(innerWest, innerEast) <= arcWidth(west, east)
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -0,0 +1,562 @@
+package what3words
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_london  = Coordinates{Lat: 51.5007, Lng: -0.1246}
+	_newYork = Coordinates{Lat: 40.6892, Lng: -74.0445}
+	_sydney  = Coordinates{Lat: -33.8688, Lng: 151.2093}
+)
+
+// _oneDegree is the length in metres of a degree of a great circle.
+const _oneDegree = _earthRadius * math.Pi / 180
+
+func TestCoordinates_DistanceTo(t *testing.T) {
+	tests := map[string]struct {
+		from     Coordinates
+		to       Coordinates
+		expected float64
+	}{
+		"same point":                 {from: _london, to: _london, expected: 0},
+		"one degree along equator":   {from: Coordinates{}, to: Coordinates{Lng: 1}, expected: _oneDegree},
+		"one degree along meridian":  {from: Coordinates{Lat: 51}, to: Coordinates{Lat: 52}, expected: _oneDegree},
+		"across the antimeridian":    {from: Coordinates{Lng: 179.5}, to: Coordinates{Lng: -179.5}, expected: _oneDegree},
+		"london to new york":         {from: _london, to: _newYork, expected: 5574848.157},
+		"london to sydney":           {from: _london, to: _sydney, expected: 16994128.093},
+		"pole to pole":               {from: Coordinates{Lat: 90}, to: Coordinates{Lat: -90}, expected: _earthRadius * math.Pi},
+		"antipodes on the equator":   {from: Coordinates{Lng: -90}, to: Coordinates{Lng: 90}, expected: _earthRadius * math.Pi},
+		"neighbouring grid squares":  {from: Coordinates{Lat: 51.520833}, to: Coordinates{Lat: 51.52086}, expected: 3.0022672},
+		"longitude outside of range": {from: Coordinates{Lng: 359}, to: Coordinates{Lng: 0}, expected: _oneDegree},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, tt.from.DistanceTo(tt.to), 1e-3)
+			assert.InDelta(t, tt.expected, tt.to.DistanceTo(tt.from), 1e-3, "distance is symmetric")
+		})
+	}
+}
+
+func TestCoordinates_BearingTo(t *testing.T) {
+	tests := map[string]struct {
+		from     Coordinates
+		to       Coordinates
+		expected float64
+	}{
+		"same point":              {from: _london, to: _london, expected: 0},
+		"north":                   {from: Coordinates{}, to: Coordinates{Lat: 1}, expected: 0},
+		"east":                    {from: Coordinates{}, to: Coordinates{Lng: 1}, expected: 90},
+		"south":                   {from: Coordinates{}, to: Coordinates{Lat: -1}, expected: 180},
+		"west":                    {from: Coordinates{}, to: Coordinates{Lng: -1}, expected: 270},
+		"across the antimeridian": {from: Coordinates{Lng: 179.5}, to: Coordinates{Lng: -179.5}, expected: 90},
+		"london to new york":      {from: _london, to: _newYork, expected: 288.33686},
+		"london to sydney":        {from: _london, to: _sydney, expected: 60.72918},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tt.from.BearingTo(tt.to)
+			assert.InDelta(t, tt.expected, got, 1e-5)
+			assert.GreaterOrEqual(t, got, 0.0)
+			assert.Less(t, got, 360.0)
+		})
+	}
+}
+
+func TestCoordinates_Destination(t *testing.T) {
+	tests := map[string]struct {
+		from     Coordinates
+		bearing  float64
+		distance float64
+		expected Coordinates
+	}{
+		"no distance":             {from: _london, bearing: 123, expected: _london},
+		"north":                   {from: Coordinates{}, bearing: 0, distance: _oneDegree, expected: Coordinates{Lat: 1}},
+		"east":                    {from: Coordinates{}, bearing: 90, distance: _oneDegree, expected: Coordinates{Lng: 1}},
+		"south west":              {from: Coordinates{}, bearing: 225, distance: 1000, expected: Coordinates{Lat: -0.0063591553, Lng: -0.0063591553}},
+		"across the antimeridian": {from: Coordinates{Lng: 179.5}, bearing: 90, distance: _oneDegree, expected: Coordinates{Lng: -179.5}},
+		"over the north pole":     {from: Coordinates{Lat: 89.5}, bearing: 0, distance: _oneDegree, expected: Coordinates{Lat: 89.5, Lng: 180}},
+		"london to new york":      {from: _london, bearing: 288.3368596615, distance: 5574848.157146, expected: _newYork},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tt.from.Destination(tt.bearing, tt.distance)
+			assert.InDelta(t, tt.expected.Lat, got.Lat, 1e-6)
+			assert.InDelta(t, tt.expected.Lng, got.Lng, 1e-6)
+		})
+	}
+}
+
+func TestCoordinates_NormaliseLongitude(t *testing.T) {
+	tests := map[string]struct {
+		lng      float64
+		expected float64
+	}{
+		"in range":          {lng: -0.1246, expected: -0.1246},
+		"antimeridian east": {lng: 180, expected: 180},
+		"antimeridian west": {lng: -180, expected: -180},
+		"just over 180":     {lng: 190, expected: -170},
+		"just under -180":   {lng: -190, expected: 170},
+		"full turn":         {lng: 360, expected: 0},
+		"several turns":     {lng: 1000, expected: -80},
+		"negative turns":    {lng: -540, expected: -180},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := Coordinates{Lat: 51.5, Lng: tt.lng}.NormaliseLongitude()
+			assert.InDelta(t, tt.expected, got.Lng, 1e-9)
+			assert.Equal(t, 51.5, got.Lat)
+		})
+	}
+}
+
+func TestSquare_Centre(t *testing.T) {
+	tests := map[string]struct {
+		square   Square
+		expected Coordinates
+	}{
+		"filled.count.soap": {
+			square: Square{
+				Southwest: Coordinates{Lat: 51.520833, Lng: -0.195543},
+				Northeast: Coordinates{Lat: 51.52086, Lng: -0.195499},
+			},
+			expected: Coordinates{Lat: 51.5208465, Lng: -0.195521},
+		},
+		"southern hemisphere": {
+			square:   Square{Southwest: Coordinates{Lat: -33.5, Lng: 151}, Northeast: Coordinates{Lat: -33.25, Lng: 151.5}},
+			expected: Coordinates{Lat: -33.375, Lng: 151.25},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tt.square.Centre()
+			assert.InDelta(t, tt.expected.Lat, got.Lat, 1e-9)
+			assert.InDelta(t, tt.expected.Lng, got.Lng, 1e-9)
+			assert.True(t, tt.square.Contains(got))
+		})
+	}
+}
+
+func TestSquare_Contains(t *testing.T) {
+	square := Square{Southwest: Coordinates{Lat: 51.5, Lng: -0.5}, Northeast: Coordinates{Lat: 51.75, Lng: 0}}
+
+	tests := map[string]struct {
+		coordinates Coordinates
+		expected    bool
+	}{
+		"inside":           {coordinates: Coordinates{Lat: 51.6, Lng: -0.25}, expected: true},
+		"south west":       {coordinates: Coordinates{Lat: 51.5, Lng: -0.5}, expected: true},
+		"southern edge":    {coordinates: Coordinates{Lat: 51.5, Lng: -0.25}, expected: true},
+		"western edge":     {coordinates: Coordinates{Lat: 51.6, Lng: -0.5}, expected: true},
+		"northern edge":    {coordinates: Coordinates{Lat: 51.75, Lng: -0.25}, expected: false},
+		"eastern edge":     {coordinates: Coordinates{Lat: 51.6, Lng: 0}, expected: false},
+		"north east":       {coordinates: Coordinates{Lat: 51.75, Lng: 0}, expected: false},
+		"outside to south": {coordinates: Coordinates{Lat: 51.4, Lng: -0.25}, expected: false},
+		"outside to west":  {coordinates: Coordinates{Lat: 51.6, Lng: -0.6}, expected: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, square.Contains(tt.coordinates))
+		})
+	}
+}
+
+func TestSquare_Neighbours(t *testing.T) {
+	square := func(south, west float64) Square {
+		return Square{
+			Southwest: Coordinates{Lat: south, Lng: west},
+			Northeast: Coordinates{Lat: south + 0.25, Lng: west + 0.5},
+		}
+	}
+
+	tests := map[string]struct {
+		square   Square
+		expected []Square
+	}{
+		"surrounded": {
+			square: square(51.5, -0.5),
+			expected: []Square{
+				square(51.75, -0.5), square(51.75, 0), square(51.5, 0), square(51.25, 0),
+				square(51.25, -0.5), square(51.25, -1), square(51.5, -1), square(51.75, -1),
+			},
+		},
+		"east of the antimeridian": {
+			square: square(10, -180),
+			expected: []Square{
+				square(10.25, -180), square(10.25, -179.5), square(10, -179.5), square(9.75, -179.5),
+				square(9.75, -180), square(9.75, 179.5), square(10, 179.5), square(10.25, 179.5),
+			},
+		},
+		"west of the antimeridian": {
+			square: square(10, 179.5),
+			expected: []Square{
+				square(10.25, 179.5), square(10.25, -180), square(10, -180), square(9.75, -180),
+				square(9.75, 179.5), square(9.75, 179), square(10, 179), square(10.25, 179),
+			},
+		},
+		"at the north pole": {
+			square: square(89.75, 0),
+			expected: []Square{
+				square(89.75, 0.5), square(89.5, 0.5), square(89.5, 0), square(89.5, -0.5), square(89.75, -0.5),
+			},
+		},
+		"at the south pole": {
+			square: square(-90, 0),
+			expected: []Square{
+				square(-89.75, 0), square(-89.75, 0.5), square(-90, 0.5), square(-90, -0.5), square(-89.75, -0.5),
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tt.square.Neighbours()
+			assert.Equal(t, tt.expected, got)
+			for _, neighbour := range got {
+				assert.False(t, neighbour.Contains(tt.square.Centre()))
+			}
+		})
+	}
+}
+
+func TestNewBoundingBoxFromCentre(t *testing.T) {
+	// dLat is the latitude in degrees of 1km, and dLng60 the longitude of 1km at 60 degrees of latitude.
+	dLat, dLng60 := 0.0089932036, 0.0179864075
+
+	tests := map[string]struct {
+		centre   Coordinates
+		radius   float64
+		expected BoundingBox
+	}{
+		"equator": {
+			centre:   Coordinates{},
+			radius:   1000,
+			expected: BoundingBox{SouthLat: -dLat, WestLng: -dLat, NorthLat: dLat, EastLng: dLat},
+		},
+		"high latitude": {
+			centre:   Coordinates{Lat: 60, Lng: 10},
+			radius:   1000,
+			expected: BoundingBox{SouthLat: 60 - dLat, WestLng: 10 - dLng60, NorthLat: 60 + dLat, EastLng: 10 + dLng60},
+		},
+		"across the antimeridian": {
+			centre:   Coordinates{Lng: 180},
+			radius:   1000,
+			expected: BoundingBox{SouthLat: -dLat, WestLng: 180 - dLat, NorthLat: dLat, EastLng: -180 + dLat},
+		},
+		"reaching the pole": {
+			centre:   Coordinates{Lat: 89.995, Lng: 10},
+			radius:   1000,
+			expected: BoundingBox{SouthLat: 89.995 - dLat, WestLng: -180, NorthLat: 90, EastLng: 180},
+		},
+		"wrapping around the globe": {
+			centre:   Coordinates{Lat: 10, Lng: 10},
+			radius:   _oneDegree * 100,
+			expected: BoundingBox{SouthLat: -90, WestLng: -180, NorthLat: 90, EastLng: 180},
+		},
+		"zero radius": {
+			centre:   _london,
+			expected: BoundingBox{SouthLat: _london.Lat, WestLng: _london.Lng, NorthLat: _london.Lat, EastLng: _london.Lng},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := NewBoundingBoxFromCentre(tt.centre, tt.radius)
+			assert.InDelta(t, tt.expected.SouthLat, got.SouthLat, 1e-9)
+			assert.InDelta(t, tt.expected.WestLng, got.WestLng, 1e-9)
+			assert.InDelta(t, tt.expected.NorthLat, got.NorthLat, 1e-9)
+			assert.InDelta(t, tt.expected.EastLng, got.EastLng, 1e-9)
+			assert.NoError(t, got.Validate())
+
+			// Points at the radius in every direction are inside the box.
+			for bearing := 0.0; bearing < 360; bearing += 15 {
+				point := tt.centre.Destination(bearing, tt.radius*(1-1e-9))
+				assert.True(t, got.Contains(point), "bearing %g: %v", bearing, point)
+			}
+		})
+	}
+}
+
+func TestBoundingBox_Contains(t *testing.T) {
+	box := BoundingBox{SouthLat: 51.5, WestLng: -0.2, NorthLat: 51.6, EastLng: 0.1}
+	antimeridian := BoundingBox{SouthLat: -16.8, WestLng: 179.98, NorthLat: -16.79, EastLng: -179.98}
+
+	tests := map[string]struct {
+		box         BoundingBox
+		coordinates Coordinates
+		expected    bool
+	}{
+		"inside":                     {box: box, coordinates: Coordinates{Lat: 51.55, Lng: 0}, expected: true},
+		"on the edge":                {box: box, coordinates: Coordinates{Lat: 51.6, Lng: 0.1}, expected: true},
+		"to the north":               {box: box, coordinates: Coordinates{Lat: 51.7, Lng: 0}, expected: false},
+		"to the east":                {box: box, coordinates: Coordinates{Lat: 51.55, Lng: 0.2}, expected: false},
+		"west of the antimeridian":   {box: antimeridian, coordinates: Coordinates{Lat: -16.795, Lng: 179.99}, expected: true},
+		"east of the antimeridian":   {box: antimeridian, coordinates: Coordinates{Lat: -16.795, Lng: -179.99}, expected: true},
+		"on the antimeridian":        {box: antimeridian, coordinates: Coordinates{Lat: -16.795, Lng: 180}, expected: true},
+		"outside a crossing box":     {box: antimeridian, coordinates: Coordinates{Lat: -16.795, Lng: 0}, expected: false},
+		"longitude outside of range": {box: antimeridian, coordinates: Coordinates{Lat: -16.795, Lng: 180.01}, expected: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.box.Contains(tt.coordinates))
+		})
+	}
+}
+
+func TestBoundingBox_Intersects(t *testing.T) {
+	box := BoundingBox{SouthLat: 51.5, WestLng: -0.2, NorthLat: 51.6, EastLng: 0.1}
+	antimeridian := BoundingBox{SouthLat: -16.8, WestLng: 179.98, NorthLat: -16.79, EastLng: -179.98}
+
+	tests := map[string]struct {
+		a, b     BoundingBox
+		expected bool
+	}{
+		"same box":         {a: box, b: box, expected: true},
+		"overlapping":      {a: box, b: BoundingBox{SouthLat: 51.55, WestLng: 0, NorthLat: 51.7, EastLng: 0.2}, expected: true},
+		"inside":           {a: box, b: BoundingBox{SouthLat: 51.52, WestLng: -0.1, NorthLat: 51.54, EastLng: 0}, expected: true},
+		"crossing":         {a: box, b: BoundingBox{SouthLat: 51, WestLng: -0.1, NorthLat: 52, EastLng: 0}, expected: true},
+		"touching":         {a: box, b: BoundingBox{SouthLat: 51.6, WestLng: 0.1, NorthLat: 51.7, EastLng: 0.2}, expected: true},
+		"to the north":     {a: box, b: BoundingBox{SouthLat: 51.7, WestLng: -0.2, NorthLat: 51.8, EastLng: 0.1}, expected: false},
+		"to the east":      {a: box, b: BoundingBox{SouthLat: 51.5, WestLng: 0.2, NorthLat: 51.6, EastLng: 0.3}, expected: false},
+		"both crossing":    {a: antimeridian, b: BoundingBox{SouthLat: -17, WestLng: 179.99, NorthLat: -16, EastLng: -179.99}, expected: true},
+		"east of crossing": {a: antimeridian, b: BoundingBox{SouthLat: -17, WestLng: -179.99, NorthLat: -16, EastLng: -179}, expected: true},
+		"west of crossing": {a: antimeridian, b: BoundingBox{SouthLat: -17, WestLng: 179, NorthLat: -16, EastLng: 179.99}, expected: true},
+		"apart from crossing": {
+			a: antimeridian, b: BoundingBox{SouthLat: -17, WestLng: -179, NorthLat: -16, EastLng: 179}, expected: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.a.Intersects(tt.b))
+			assert.Equal(t, tt.expected, tt.b.Intersects(tt.a), "intersection is symmetric")
+		})
+	}
+}
+
+func TestBoundingBox_Union(t *testing.T) {
+	tests := map[string]struct {
+		a, b     BoundingBox
+		expected BoundingBox
+	}{
+		"inside": {
+			a:        BoundingBox{SouthLat: 51, WestLng: -1, NorthLat: 52, EastLng: 1},
+			b:        BoundingBox{SouthLat: 51.5, WestLng: 0, NorthLat: 51.6, EastLng: 0.1},
+			expected: BoundingBox{SouthLat: 51, WestLng: -1, NorthLat: 52, EastLng: 1},
+		},
+		"overlapping": {
+			a:        BoundingBox{SouthLat: 51, WestLng: -1, NorthLat: 52, EastLng: 1},
+			b:        BoundingBox{SouthLat: 50, WestLng: 0, NorthLat: 51.5, EastLng: 2},
+			expected: BoundingBox{SouthLat: 50, WestLng: -1, NorthLat: 52, EastLng: 2},
+		},
+		"apart": {
+			a:        BoundingBox{SouthLat: 51, WestLng: -1, NorthLat: 52, EastLng: 1},
+			b:        BoundingBox{SouthLat: 40, WestLng: -75, NorthLat: 41, EastLng: -73},
+			expected: BoundingBox{SouthLat: 40, WestLng: -75, NorthLat: 52, EastLng: 1},
+		},
+		"either side of the antimeridian": {
+			a:        BoundingBox{SouthLat: -17, WestLng: 179, NorthLat: -16, EastLng: 179.5},
+			b:        BoundingBox{SouthLat: -18, WestLng: -179.5, NorthLat: -17, EastLng: -179},
+			expected: BoundingBox{SouthLat: -18, WestLng: 179, NorthLat: -16, EastLng: -179},
+		},
+		"crossing the antimeridian": {
+			a:        BoundingBox{SouthLat: -17, WestLng: 179, NorthLat: -16, EastLng: -179},
+			b:        BoundingBox{SouthLat: -17, WestLng: -178, NorthLat: -16, EastLng: -177},
+			expected: BoundingBox{SouthLat: -17, WestLng: 179, NorthLat: -16, EastLng: -177},
+		},
+		"around the globe": {
+			a:        BoundingBox{SouthLat: 0, WestLng: 10, NorthLat: 1, EastLng: -170},
+			b:        BoundingBox{SouthLat: 0, WestLng: -175, NorthLat: 1, EastLng: 15},
+			expected: BoundingBox{SouthLat: 0, WestLng: -180, NorthLat: 1, EastLng: 180},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.a.Union(tt.b))
+			assert.Equal(t, tt.expected, tt.b.Union(tt.a), "union is symmetric")
+		})
+	}
+}
+
+func TestBoundingBox_Expand(t *testing.T) {
+	dLat := 0.0089932036
+
+	tests := map[string]struct {
+		box      BoundingBox
+		metres   float64
+		expected BoundingBox
+	}{
+		"no distance": {
+			box:      BoundingBox{SouthLat: 51.5, WestLng: -0.2, NorthLat: 51.6, EastLng: 0.1},
+			expected: BoundingBox{SouthLat: 51.5, WestLng: -0.2, NorthLat: 51.6, EastLng: 0.1},
+		},
+		"widest at the edge nearest a pole": {
+			box:      BoundingBox{SouthLat: 0, WestLng: 10, NorthLat: 60, EastLng: 20},
+			metres:   1000,
+			expected: BoundingBox{SouthLat: -dLat, WestLng: 10 - 0.0179864075, NorthLat: 60 + dLat, EastLng: 20 + 0.0179864075},
+		},
+		"southern hemisphere": {
+			box:      BoundingBox{SouthLat: -60, WestLng: 10, NorthLat: -30, EastLng: 20},
+			metres:   1000,
+			expected: BoundingBox{SouthLat: -60 - dLat, WestLng: 10 - 0.0179864075, NorthLat: -30 + dLat, EastLng: 20 + 0.0179864075},
+		},
+		"onto the antimeridian": {
+			box:      BoundingBox{SouthLat: -1, WestLng: 170, NorthLat: 0, EastLng: 179.995},
+			metres:   1000,
+			expected: BoundingBox{SouthLat: -1 - dLat, WestLng: 170 - 0.0089945736, NorthLat: dLat, EastLng: -179.9960054264},
+		},
+		"to a pole": {
+			box:      BoundingBox{SouthLat: -89.995, WestLng: 10, NorthLat: -89, EastLng: 20},
+			metres:   1000,
+			expected: BoundingBox{SouthLat: -90, WestLng: -180, NorthLat: -89 + dLat, EastLng: 180},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tt.box.Expand(tt.metres)
+			assert.InDelta(t, tt.expected.SouthLat, got.SouthLat, 1e-9)
+			assert.InDelta(t, tt.expected.WestLng, got.WestLng, 1e-9)
+			assert.InDelta(t, tt.expected.NorthLat, got.NorthLat, 1e-9)
+			assert.InDelta(t, tt.expected.EastLng, got.EastLng, 1e-9)
+		})
+	}
+}
+
+func TestBoundingBox_Area(t *testing.T) {
+	tests := map[string]struct {
+		box      BoundingBox
+		expected float64
+	}{
+		"degree at the equator": {
+			box:      BoundingBox{SouthLat: 0, WestLng: 0, NorthLat: 1, EastLng: 1},
+			expected: 12363718145.18,
+		},
+		"degree in london": {
+			box:      BoundingBox{SouthLat: 51, WestLng: 0, NorthLat: 52, EastLng: 1},
+			expected: 7696888582.57,
+		},
+		"across the antimeridian": {
+			box:      BoundingBox{SouthLat: 0, WestLng: 179.5, NorthLat: 1, EastLng: -179.5},
+			expected: 12363718145.18,
+		},
+		"northern hemisphere": {
+			box:      BoundingBox{SouthLat: 0, WestLng: -180, NorthLat: 90, EastLng: 180},
+			expected: 2 * math.Pi * _earthRadius * _earthRadius,
+		},
+		"single point": {
+			box:      BoundingBox{SouthLat: 51.5, WestLng: -0.1, NorthLat: 51.5, EastLng: -0.1},
+			expected: 0,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, tt.box.Area(), 1)
+		})
+	}
+}
+
+func TestPolygonCoordinates_Contains(t *testing.T) {
+	// concave is a U shape, open to the north, with the gap between its arms from longitude 1 to 2.
+	concave := PolygonCoordinates{
+		{Lat: 0, Lng: 0}, {Lat: 0, Lng: 3}, {Lat: 3, Lng: 3}, {Lat: 3, Lng: 2},
+		{Lat: 1, Lng: 2}, {Lat: 1, Lng: 1}, {Lat: 3, Lng: 1}, {Lat: 3, Lng: 0}, {Lat: 0, Lng: 0},
+	}
+
+	tests := map[string]struct {
+		polygon     PolygonCoordinates
+		coordinates Coordinates
+		expected    bool
+	}{
+		"inside":               {polygon: closedPolygon(5), coordinates: Coordinates{Lat: 51.52, Lng: -0.19}, expected: true},
+		"outside":              {polygon: closedPolygon(5), coordinates: Coordinates{Lat: 51.52, Lng: -0.1}, expected: false},
+		"inside an arm":        {polygon: concave, coordinates: Coordinates{Lat: 2, Lng: 0.5}, expected: true},
+		"inside the base":      {polygon: concave, coordinates: Coordinates{Lat: 0.5, Lng: 1.5}, expected: true},
+		"in the gap":           {polygon: concave, coordinates: Coordinates{Lat: 2, Lng: 1.5}, expected: false},
+		"not closed":           {polygon: concave[:len(concave)-1], coordinates: Coordinates{Lat: 2, Lng: 2.5}, expected: true},
+		"level with a vertex":  {polygon: concave, coordinates: Coordinates{Lat: 1, Lng: 0.5}, expected: true},
+		"beyond a vertex":      {polygon: concave, coordinates: Coordinates{Lat: 3, Lng: 4}, expected: false},
+		"empty polygon":        {polygon: PolygonCoordinates{}, coordinates: Coordinates{}, expected: false},
+		"polygon with no area": {polygon: PolygonCoordinates{{Lat: 0, Lng: 0}, {Lat: 1, Lng: 1}, {Lat: 0, Lng: 0}}, coordinates: Coordinates{Lat: 0.5, Lng: 0.5}, expected: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.polygon.Contains(tt.coordinates))
+		})
+	}
+}
+
+func TestPolygonCoordinates_Area(t *testing.T) {
+	square := PolygonCoordinates{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 1}, {Lat: 1, Lng: 1}, {Lat: 1, Lng: 0}, {Lat: 0, Lng: 0}}
+	reversed := make(PolygonCoordinates, len(square))
+	for i, c := range square {
+		reversed[len(square)-1-i] = c
+	}
+
+	tests := map[string]struct {
+		polygon  PolygonCoordinates
+		expected float64
+	}{
+		"degree at the equator": {polygon: square, expected: 12363718145.18},
+		"reversed":              {polygon: reversed, expected: 12363718145.18},
+		"not closed":            {polygon: square[:4], expected: 12363718145.18},
+		"triangle":              {polygon: PolygonCoordinates{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 1}, {Lat: 1, Lng: 0}}, expected: 6181859072.59},
+		"across the antimeridian": {
+			polygon:  PolygonCoordinates{{Lat: 0, Lng: 179.5}, {Lat: 0, Lng: -179.5}, {Lat: 1, Lng: -179.5}, {Lat: 1, Lng: 179.5}},
+			expected: 12363718145.18,
+		},
+		"degree in london": {
+			polygon:  PolygonCoordinates{{Lat: 51, Lng: 0}, {Lat: 51, Lng: 1}, {Lat: 52, Lng: 1}, {Lat: 52, Lng: 0}},
+			expected: 7696888582.57,
+		},
+		"line":  {polygon: PolygonCoordinates{{Lat: 0, Lng: 0}, {Lat: 1, Lng: 1}, {Lat: 0, Lng: 0}}, expected: 0},
+		"empty": {polygon: PolygonCoordinates{}, expected: 0},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, tt.polygon.Area(), 1)
+		})
+	}
+}
+
+func TestPolygonCoordinates_Centroid(t *testing.T) {
+	tests := map[string]struct {
+		polygon  PolygonCoordinates
+		expected Coordinates
+	}{
+		"square": {
+			polygon:  PolygonCoordinates{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 2}, {Lat: 2, Lng: 2}, {Lat: 2, Lng: 0}, {Lat: 0, Lng: 0}},
+			expected: Coordinates{Lat: 1, Lng: 1},
+		},
+		"not closed": {
+			polygon:  PolygonCoordinates{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 2}, {Lat: 2, Lng: 2}, {Lat: 2, Lng: 0}},
+			expected: Coordinates{Lat: 1, Lng: 1},
+		},
+		"triangle": {
+			polygon:  PolygonCoordinates{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 3}, {Lat: 3, Lng: 0}, {Lat: 0, Lng: 0}},
+			expected: Coordinates{Lat: 1, Lng: 1},
+		},
+		"weighted by area": {
+			// An L shape made of a 2x1 rectangle and a 1x1 square above its western end.
+			polygon:  PolygonCoordinates{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 2}, {Lat: 1, Lng: 2}, {Lat: 1, Lng: 1}, {Lat: 2, Lng: 1}, {Lat: 2, Lng: 0}},
+			expected: Coordinates{Lat: 5.0 / 6, Lng: 5.0 / 6},
+		},
+		"across the antimeridian": {
+			polygon:  PolygonCoordinates{{Lat: 0, Lng: 179}, {Lat: 0, Lng: -179}, {Lat: 2, Lng: -179}, {Lat: 2, Lng: 179}},
+			expected: Coordinates{Lat: 1, Lng: 180},
+		},
+		"circle in london": {polygon: closedPolygon(25), expected: Coordinates{Lat: 51.52, Lng: -0.19}},
+		"line": {
+			polygon:  PolygonCoordinates{{Lat: 0, Lng: 0}, {Lat: 2, Lng: 2}},
+			expected: Coordinates{Lat: 1, Lng: 1},
+		},
+		"single point": {polygon: PolygonCoordinates{_london}, expected: _london},
+		"empty":        {polygon: PolygonCoordinates{}, expected: Coordinates{}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tt.polygon.Centroid()
+			assert.InDelta(t, tt.expected.Lat, got.Lat, 1e-9)
+			assert.InDelta(t, tt.expected.Lng, got.Lng, 1e-9)
+		})
+	}
+}
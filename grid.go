@@ -0,0 +1,224 @@
+package what3words
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+const (
+	// _gridTileDiagonalKm is the largest diagonal of the tiles requested by GridSectionLarge. The API rejects boxes
+	// with a diagonal over 4km, and the margin allows for the rounding of coordinates in the request.
+	_gridTileDiagonalKm = 3.9
+
+	// _gridMaxTiles is the largest number of tiles GridSectionLarge requests for a box, which covers at most roughly
+	// 7,500 square kilometres. It bounds the requests made against the quota and the lines held in memory.
+	_gridMaxTiles = 1000
+
+	// _gridLineTolerance is the distance in degrees within which grid lines from neighbouring tiles are joined.
+	// It is far smaller than a grid square, so separate lines are never joined.
+	_gridLineTolerance = 1e-6
+)
+
+// GridSectionLarge returns a section of the What3Words grid for a bounding box of any size. Boxes with a diagonal
+// over the 4km allowed by GridSection are split into tiles, which are requested with up to BatchOptions.Concurrency
+// requests at once, and BatchOptions.Progress is called as each tile completes. The lines of the tiles are merged,
+// so lines crossing the seams between tiles are returned once, spanning the whole box. Boxes crossing the
+// antimeridian, whose western longitude is greater than their eastern longitude, are split at 180 degrees.
+// Each tile counts as a request towards your plan's quota, and the first tile to fail fails the whole request.
+// Boxes needing more than 1000 tiles are rejected with a ValidationError before any request is sent.
+func (w *w3w) GridSectionLarge(ctx context.Context, box *BoundingBox, opts BatchOptions, callOpts ...CallOption) (*GridSection, error) {
+	if box == nil {
+		return nil, fmt.Errorf("retrieving large grid section: %w", &ValidationError{Field: "bounding-box", Message: "must be specified"})
+	}
+	if err := box.Validate(); err != nil {
+		return nil, fmt.Errorf("retrieving large grid section: %w", err)
+	}
+
+	tiles := gridTiles(*box)
+	if len(tiles) > _gridMaxTiles {
+		return nil, fmt.Errorf("retrieving large grid section: %w", &ValidationError{
+			Field:   "bounding-box",
+			Message: fmt.Sprintf("needs more than %d tiles", _gridMaxTiles),
+		})
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		lines    []GridLine
+		firstErr error
+	)
+	next := forEach(ctx, len(tiles), opts, func(ctx context.Context, i int) {
+		section, err := w.GridSection(ctx, &tiles[i], callOpts...)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+				cancel()
+			}
+			return
+		}
+		lines = append(lines, section.Lines...)
+	})
+
+	switch {
+	case firstErr != nil:
+		return nil, fmt.Errorf("retrieving large grid section: %w", firstErr)
+	case next < len(tiles):
+		return nil, fmt.Errorf("retrieving large grid section: %w", ctx.Err())
+	}
+
+	return &GridSection{Lines: mergeGridLines(lines)}, nil
+}
+
+// gridTiles splits the box at the antimeridian, if it crosses it, and then into tiles small enough for GridSection.
+// It stops once there are more than _gridMaxTiles tiles, so the tiles only cover the box if there are no more.
+func gridTiles(box BoundingBox) []BoundingBox {
+	boxes := []BoundingBox{box}
+	if box.WestLng > box.EastLng {
+		boxes = []BoundingBox{
+			{SouthLat: box.SouthLat, WestLng: box.WestLng, NorthLat: box.NorthLat, EastLng: 180},
+			{SouthLat: box.SouthLat, WestLng: -180, NorthLat: box.NorthLat, EastLng: box.EastLng},
+		}
+	}
+
+	var tiles []BoundingBox
+	for _, b := range boxes {
+		tiles = appendTiles(tiles, b)
+	}
+	return tiles
+}
+
+// appendTiles appends the box to tiles if it is small enough, or otherwise halves its longer side and appends the
+// tiles of each half.
+func appendTiles(tiles []BoundingBox, box BoundingBox) []BoundingBox {
+	if len(tiles) > _gridMaxTiles {
+		return tiles
+	}
+	if d := diagonalKm(box); d <= _gridTileDiagonalKm || math.IsNaN(d) {
+		return append(tiles, box)
+	}
+
+	// The box is widest on the edge nearest the equator.
+	widest := 0.0
+	if box.SouthLat > 0 || box.NorthLat < 0 {
+		widest = math.Min(math.Abs(box.SouthLat), math.Abs(box.NorthLat))
+	}
+	width := math.Abs(box.EastLng-box.WestLng) * math.Cos(widest*math.Pi/180)
+	height := math.Abs(box.NorthLat - box.SouthLat)
+
+	first, second := box, box
+	if width > height {
+		mid := box.WestLng + (box.EastLng-box.WestLng)/2
+		first.EastLng, second.WestLng = mid, mid
+	} else {
+		mid := box.SouthLat + (box.NorthLat-box.SouthLat)/2
+		first.NorthLat, second.SouthLat = mid, mid
+	}
+
+	return appendTiles(appendTiles(tiles, first), second)
+}
+
+// diagonalKm returns the great circle distance in kilometres between the south west and north east corners of the box.
+func diagonalKm(box BoundingBox) float64 {
+	southwest := Coordinates{Lat: box.SouthLat, Lng: box.WestLng}
+	return southwest.DistanceTo(Coordinates{Lat: box.NorthLat, Lng: box.EastLng}) / 1000
+}
+
+// segment is a grid line along a line of latitude or longitude, at position along the axis from one end to the other.
+type segment struct {
+	position float64
+	from, to float64
+}
+
+// mergeGridLines removes duplicate lines and joins lines which continue each other, such as the parts of a line
+// which crosses the seam between two tiles. Horizontal lines are returned first, from south to north,
+// followed by vertical lines from west to east.
+func mergeGridLines(lines []GridLine) []GridLine {
+	var horizontal, vertical []segment
+	var other []GridLine
+	for _, line := range lines {
+		switch {
+		case math.Abs(line.Start.Lat-line.End.Lat) < _gridLineTolerance:
+			horizontal = append(horizontal, segment{
+				position: line.Start.Lat,
+				from:     math.Min(line.Start.Lng, line.End.Lng),
+				to:       math.Max(line.Start.Lng, line.End.Lng),
+			})
+		case math.Abs(line.Start.Lng-line.End.Lng) < _gridLineTolerance:
+			vertical = append(vertical, segment{
+				position: line.Start.Lng,
+				from:     math.Min(line.Start.Lat, line.End.Lat),
+				to:       math.Max(line.Start.Lat, line.End.Lat),
+			})
+		default:
+			other = append(other, line)
+		}
+	}
+
+	merged := make([]GridLine, 0, len(lines))
+	for _, s := range mergeSegments(horizontal) {
+		merged = append(merged, GridLine{
+			Start: Coordinates{Lat: s.position, Lng: s.from},
+			End:   Coordinates{Lat: s.position, Lng: s.to},
+		})
+	}
+	for _, s := range mergeSegments(vertical) {
+		merged = append(merged, GridLine{
+			Start: Coordinates{Lat: s.from, Lng: s.position},
+			End:   Coordinates{Lat: s.to, Lng: s.position},
+		})
+	}
+
+	seen := make(map[GridLine]bool, len(other))
+	for _, line := range other {
+		if !seen[line] {
+			seen[line] = true
+			merged = append(merged, line)
+		}
+	}
+
+	return merged
+}
+
+// mergeSegments joins segments at the same position which overlap or touch.
+func mergeSegments(segments []segment) []segment {
+	if len(segments) == 0 {
+		return nil
+	}
+
+	// Positions which differ by less than the tolerance are the same line, so snap them to the first of them
+	// before sorting along the line.
+	sort.Slice(segments, func(i, j int) bool { return segments[i].position < segments[j].position })
+	position := segments[0].position
+	for i := range segments {
+		if segments[i].position-position >= _gridLineTolerance {
+			position = segments[i].position
+		}
+		segments[i].position = position
+	}
+	sort.SliceStable(segments, func(i, j int) bool {
+		if segments[i].position != segments[j].position {
+			return segments[i].position < segments[j].position
+		}
+		return segments[i].from < segments[j].from
+	})
+
+	merged := []segment{segments[0]}
+	for _, s := range segments[1:] {
+		last := &merged[len(merged)-1]
+		if s.position == last.position && s.from <= last.to+_gridLineTolerance {
+			last.to = math.Max(last.to, s.to)
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
@@ -0,0 +1,277 @@
+package what3words
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// _latticeSpacing is the spacing in degrees of the lines of the lattice served by newLatticeServer.
+const _latticeSpacing = 0.005
+
+// latticeLines returns the lines of a lattice with _latticeSpacing between lines which are inside the box,
+// each spanning the box, in the same way as the grid-section endpoint.
+func latticeLines(box BoundingBox) []GridLine {
+	var lines []GridLine
+	for i := math.Ceil(box.SouthLat / _latticeSpacing); i*_latticeSpacing <= box.NorthLat; i++ {
+		lines = append(lines, GridLine{
+			Start: Coordinates{Lat: i * _latticeSpacing, Lng: box.WestLng},
+			End:   Coordinates{Lat: i * _latticeSpacing, Lng: box.EastLng},
+		})
+	}
+	for i := math.Ceil(box.WestLng / _latticeSpacing); i*_latticeSpacing <= box.EastLng; i++ {
+		lines = append(lines, GridLine{
+			Start: Coordinates{Lat: box.SouthLat, Lng: i * _latticeSpacing},
+			End:   Coordinates{Lat: box.NorthLat, Lng: i * _latticeSpacing},
+		})
+	}
+	return lines
+}
+
+// newLatticeServer serves the lines of the lattice from grid-section, rejecting boxes with a diagonal over 4km.
+// Boxes for which fail returns true get an internal server error.
+func newLatticeServer(t *testing.T, fail func(BoundingBox) bool) (*httptest.Server, func() []BoundingBox) {
+	t.Helper()
+
+	var (
+		mu    sync.Mutex
+		boxes []BoundingBox
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var v []float64
+		for _, part := range strings.Split(r.URL.Query().Get("bounding-box"), ",") {
+			f, err := strconv.ParseFloat(part, 64)
+			assert.NoError(t, err)
+			v = append(v, f)
+		}
+		box := BoundingBox{SouthLat: v[0], WestLng: v[1], NorthLat: v[2], EastLng: v[3]}
+
+		mu.Lock()
+		boxes = append(boxes, box)
+		mu.Unlock()
+
+		switch {
+		case diagonalKm(box) > 4 || box.WestLng > box.EastLng:
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"error": {"code": "BadBoundingBoxTooBig", "message": "The diagonal of bounding-box may not be greater than 4km"}}`))
+			return
+		case fail != nil && fail(box):
+			rw.WriteHeader(http.StatusInternalServerError)
+			_, _ = rw.Write([]byte(`{"error": {"code": "InternalServerError", "message": "Internal Server Error"}}`))
+			return
+		}
+		_ = json.NewEncoder(rw).Encode(GridSection{Lines: latticeLines(box)})
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts, func() []BoundingBox {
+		mu.Lock()
+		defer mu.Unlock()
+		return boxes
+	}
+}
+
+func TestGridTiles(t *testing.T) {
+	tests := map[string]struct {
+		box           BoundingBox
+		expectedTiles int
+		expectedArea  float64
+	}{
+		"small box": {
+			box:           BoundingBox{SouthLat: 52.207988, WestLng: 0.116126, NorthLat: 52.208867, EastLng: 0.117540},
+			expectedTiles: 1,
+			expectedArea:  0.000879 * 0.001414,
+		},
+		"wide box": {
+			box:           BoundingBox{SouthLat: 51.5, WestLng: -0.2, NorthLat: 51.52, EastLng: -0.1},
+			expectedTiles: 4,
+			expectedArea:  0.02 * 0.1,
+		},
+		"box across the equator": {
+			box:           BoundingBox{SouthLat: -0.05, WestLng: 10, NorthLat: 0.05, EastLng: 10.05},
+			expectedTiles: 16,
+			expectedArea:  0.1 * 0.05,
+		},
+		"box across the antimeridian": {
+			box:           BoundingBox{SouthLat: -16.8, WestLng: 179.98, NorthLat: -16.79, EastLng: -179.98},
+			expectedTiles: 2,
+			expectedArea:  0.01 * 0.04,
+		},
+		"box over the tile limit": {
+			box:           BoundingBox{SouthLat: 35, WestLng: -10, NorthLat: 70, EastLng: 40},
+			expectedTiles: _gridMaxTiles + 1,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tiles := gridTiles(tt.box)
+			assert.Len(t, tiles, tt.expectedTiles)
+
+			area := 0.0
+			for _, tile := range tiles {
+				assert.LessOrEqual(t, diagonalKm(tile), _gridTileDiagonalKm)
+				assert.LessOrEqual(t, tile.WestLng, tile.EastLng)
+				assert.GreaterOrEqual(t, tile.SouthLat, tt.box.SouthLat)
+				assert.LessOrEqual(t, tile.NorthLat, tt.box.NorthLat)
+				area += (tile.NorthLat - tile.SouthLat) * (tile.EastLng - tile.WestLng)
+			}
+			if tt.expectedTiles <= _gridMaxTiles {
+				assert.InDelta(t, tt.expectedArea, area, 1e-12, "tiles cover the box without overlapping")
+			}
+		})
+	}
+}
+
+func TestMergeGridLines(t *testing.T) {
+	horizontal := func(lat, west, east float64) GridLine {
+		return GridLine{Start: Coordinates{Lat: lat, Lng: west}, End: Coordinates{Lat: lat, Lng: east}}
+	}
+	vertical := func(lng, south, north float64) GridLine {
+		return GridLine{Start: Coordinates{Lat: south, Lng: lng}, End: Coordinates{Lat: north, Lng: lng}}
+	}
+
+	tests := map[string]struct {
+		lines    []GridLine
+		expected []GridLine
+	}{
+		"no lines": {
+			expected: []GridLine{},
+		},
+		"duplicates at a seam": {
+			lines:    []GridLine{vertical(0.1, 51, 51.01), vertical(0.1, 51, 51.01)},
+			expected: []GridLine{vertical(0.1, 51, 51.01)},
+		},
+		"collinear lines across a seam": {
+			lines:    []GridLine{horizontal(51.005, 0.05, 0.1), horizontal(51.005, 0, 0.05)},
+			expected: []GridLine{horizontal(51.005, 0, 0.1)},
+		},
+		"overlapping lines": {
+			lines:    []GridLine{vertical(0.1, 51, 51.02), vertical(0.1, 51.01, 51.03)},
+			expected: []GridLine{vertical(0.1, 51, 51.03)},
+		},
+		"lines with a gap": {
+			lines:    []GridLine{horizontal(51.005, 0, 0.05), horizontal(51.005, 0.06, 0.1)},
+			expected: []GridLine{horizontal(51.005, 0, 0.05), horizontal(51.005, 0.06, 0.1)},
+		},
+		"rounding differences": {
+			lines:    []GridLine{horizontal(51.0050000001, 0, 0.05), horizontal(51.005, 0.0500000001, 0.1)},
+			expected: []GridLine{horizontal(51.005, 0, 0.1)},
+		},
+		"reversed lines": {
+			lines:    []GridLine{horizontal(51.005, 0.1, 0.05), vertical(0.1, 51.01, 51)},
+			expected: []GridLine{horizontal(51.005, 0.05, 0.1), vertical(0.1, 51, 51.01)},
+		},
+		"sorted horizontal then vertical": {
+			lines:    []GridLine{vertical(0.2, 51, 51.01), horizontal(51.01, 0, 1), vertical(0.1, 51, 51.01), horizontal(51, 0, 1)},
+			expected: []GridLine{horizontal(51, 0, 1), horizontal(51.01, 0, 1), vertical(0.1, 51, 51.01), vertical(0.2, 51, 51.01)},
+		},
+		"diagonal lines": {
+			lines: []GridLine{
+				{Start: Coordinates{Lat: 51, Lng: 0}, End: Coordinates{Lat: 51.01, Lng: 0.01}},
+				{Start: Coordinates{Lat: 51, Lng: 0}, End: Coordinates{Lat: 51.01, Lng: 0.01}},
+			},
+			expected: []GridLine{{Start: Coordinates{Lat: 51, Lng: 0}, End: Coordinates{Lat: 51.01, Lng: 0.01}}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mergeGridLines(tt.lines))
+		})
+	}
+}
+
+func TestW3w_GridSectionLarge(t *testing.T) {
+	tests := map[string]struct {
+		box           BoundingBox
+		expectedTiles int
+	}{
+		"small box": {
+			box:           BoundingBox{SouthLat: 51.5, WestLng: -0.2, NorthLat: 51.51, EastLng: -0.19},
+			expectedTiles: 1,
+		},
+		"large box": {
+			box:           BoundingBox{SouthLat: 51.5, WestLng: -0.2, NorthLat: 51.55, EastLng: -0.1},
+			expectedTiles: 8,
+		},
+		"box across the antimeridian": {
+			box:           BoundingBox{SouthLat: -16.8, WestLng: 179.98, NorthLat: -16.79, EastLng: -179.98},
+			expectedTiles: 2,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts, boxes := newLatticeServer(t, nil)
+			u, err := url.Parse(ts.URL)
+			assert.NoError(t, err)
+
+			var progress []int
+			w := NewClient("example-api-key", WithEndpoint(u))
+			got, err := w.GridSectionLarge(context.Background(), &tt.box, BatchOptions{
+				Concurrency: 4,
+				Progress:    func(completed, total int) { progress = append(progress, completed) },
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Len(t, boxes(), tt.expectedTiles)
+			assert.Len(t, progress, tt.expectedTiles)
+
+			var expected []GridLine
+			for _, box := range gridTiles(tt.box) {
+				expected = append(expected, latticeLines(box)...)
+			}
+			assert.Equal(t, mergeGridLines(expected), got.Lines)
+
+			// Every line of the lattice is returned once, spanning the box.
+			for _, line := range got.Lines {
+				if line.Start.Lat == line.End.Lat && tt.box.WestLng < tt.box.EastLng {
+					assert.Equal(t, tt.box.WestLng, line.Start.Lng)
+					assert.Equal(t, tt.box.EastLng, line.End.Lng)
+				}
+			}
+		})
+	}
+}
+
+func TestW3w_GridSectionLargeError(t *testing.T) {
+	// The tiles of the eastern half of the box fail.
+	ts, boxes := newLatticeServer(t, func(box BoundingBox) bool { return box.WestLng >= -0.15 })
+	u, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	w := NewClient("example-api-key", WithEndpoint(u))
+	_, err = w.GridSectionLarge(context.Background(), NewBoundingBox(51.5, -0.2, 51.55, -0.1), BatchOptions{Concurrency: 1})
+	assert.ErrorIs(t, err, ErrInternalServerError)
+	assert.Len(t, boxes(), 5, "no more tiles are requested after a failure")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = w.GridSectionLarge(ctx, NewBoundingBox(51.5, -0.2, 51.55, -0.1), BatchOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestW3w_GridSectionLargeTooLarge(t *testing.T) {
+	ts, boxes := newLatticeServer(t, nil)
+	u, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	// Europe.
+	w := NewClient("example-api-key", WithEndpoint(u))
+	_, err = w.GridSectionLarge(context.Background(), NewBoundingBox(35, -10, 70, 40), BatchOptions{})
+
+	var validationErr *ValidationError
+	if assert.ErrorAs(t, err, &validationErr) {
+		assert.Equal(t, "bounding-box", validationErr.Field)
+	}
+	assert.EqualError(t, err, "retrieving large grid section: invalid bounding-box: needs more than 1000 tiles")
+	assert.Empty(t, boxes(), "no tiles are requested")
+}
@@ -0,0 +1,257 @@
+package what3words
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// _defaultKeyQuarantine is how long a key is skipped after a quota or key error if KeyPool.Quarantine is not set.
+const _defaultKeyQuarantine = 10 * time.Minute
+
+// ErrAllKeysQuarantined is returned when every key configured with WithAPIKeys is quarantined.
+var ErrAllKeysQuarantined = errors.New("all API keys are quarantined")
+
+// ErrNoKeysConfigured is returned by every request of a client configured with WithAPIKeys without any keys.
+var ErrNoKeysConfigured = errors.New("no API keys configured")
+
+// KeyStrategy selects which of the keys configured with WithAPIKeys serves each request.
+type KeyStrategy int
+
+const (
+	// KeyStrategyRoundRobin uses each available key in turn.
+	KeyStrategyRoundRobin KeyStrategy = iota
+
+	// KeyStrategyWeighted spreads requests over the available keys in proportion to their weights.
+	KeyStrategyWeighted
+
+	// KeyStrategyPrimary uses the first available key, so later keys are only used while earlier keys are quarantined.
+	KeyStrategyPrimary
+)
+
+// APIKey is one of the keys configured with WithAPIKeys.
+type APIKey struct {
+	// Key is the what3words API key.
+	Key string
+
+	// Name identifies the key in KeyStats and WithServedKey without revealing it. Defaults to the key with all but
+	// its last 4 characters masked.
+	Name string
+
+	// Weight is the share of requests the key serves with KeyStrategyWeighted. Defaults to 1.
+	Weight int
+}
+
+// KeyPool configures the keys used by a client.
+type KeyPool struct {
+	// Keys are the keys to use, in order of preference for KeyStrategyPrimary.
+	Keys []APIKey
+
+	// Strategy selects which key serves each request. Defaults to KeyStrategyRoundRobin.
+	Strategy KeyStrategy
+
+	// Quarantine is how long a key is skipped after the API rejects it with QuotaExceeded, InvalidKey or
+	// SuspendedKey. A longer Retry-After returned with the error takes precedence. Defaults to 10 minutes.
+	Quarantine time.Duration
+}
+
+// KeyStats contains the usage of a key configured with WithAPIKeys.
+type KeyStats struct {
+	// Name is the name of the key.
+	Name string
+
+	// Requests is the number of requests sent with the key, including failed requests.
+	Requests uint64
+
+	// Failures is the number of requests sent with the key which failed.
+	Failures uint64
+
+	// QuarantinedUntil is when the key will next be used, or the zero time if it is not quarantined.
+	QuarantinedUntil time.Time
+}
+
+// WithAPIKeys is a Functional Option for spreading requests over several API keys, for example keys belonging to
+// different business units or plans. When the API rejects a key with QuotaExceeded, InvalidKey or SuspendedKey, the
+// key is quarantined and the request is immediately sent again with the next available key, so it only fails once
+// every key has been rejected. The keys replace the key passed to NewClient. If the pool has no keys, every request
+// fails with ErrNoKeysConfigured.
+func WithAPIKeys(pool KeyPool) Option {
+	return func(w *w3w) {
+		if len(pool.Keys) == 0 && w.configErr == nil {
+			w.configErr = fmt.Errorf("configuring API keys: %w", ErrNoKeysConfigured)
+		}
+		w.keys = newKeyPool(pool)
+	}
+}
+
+// KeyStats returns the usage of each key configured with WithAPIKeys, in the order they were configured.
+// It returns nil if the client uses a single key.
+func (w *w3w) KeyStats() []KeyStats {
+	if w.keys == nil {
+		return nil
+	}
+	return w.keys.stats()
+}
+
+// keyPool selects keys for requests and tracks their usage. It is safe for concurrent use.
+type keyPool struct {
+	strategy   KeyStrategy
+	quarantine time.Duration
+	now        func() time.Time
+
+	mu   sync.Mutex
+	keys []*pooledKey
+	next int
+}
+
+// pooledKey is a key in a keyPool with its usage.
+type pooledKey struct {
+	APIKey
+
+	// current is the running weight of the key for smooth weighted round robin.
+	current int
+
+	requests         uint64
+	failures         uint64
+	quarantinedUntil time.Time
+}
+
+func newKeyPool(pool KeyPool) *keyPool {
+	p := &keyPool{strategy: pool.Strategy, quarantine: pool.Quarantine, now: time.Now}
+	if p.quarantine <= 0 {
+		p.quarantine = _defaultKeyQuarantine
+	}
+
+	for _, key := range pool.Keys {
+		if key.Name == "" {
+			key.Name = maskKey(key.Key)
+		}
+		if key.Weight <= 0 {
+			key.Weight = 1
+		}
+		p.keys = append(p.keys, &pooledKey{APIKey: key})
+	}
+
+	return p
+}
+
+// maskKey returns the key with all but its last 4 characters replaced by asterisks, or the empty string if there is
+// no key.
+func maskKey(key string) string {
+	if key == "" {
+		return ""
+	}
+	if len(key) <= 4 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
+
+// acquire selects the key for a request according to the strategy, skipping quarantined keys and keys which have
+// already been tried for the request. It returns nil if every available key has been tried,
+// ErrNoKeysConfigured if the pool has no keys, and ErrAllKeysQuarantined if no key was available in the first place.
+func (p *keyPool) acquire(tried map[*pooledKey]bool) (*pooledKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.keys) == 0 {
+		return nil, ErrNoKeysConfigured
+	}
+
+	now := p.now()
+	available := func(k *pooledKey) bool {
+		return !tried[k] && !now.Before(k.quarantinedUntil)
+	}
+
+	switch p.strategy {
+	case KeyStrategyWeighted:
+		// Smooth weighted round robin, which interleaves keys rather than sending runs of requests to one key.
+		var (
+			chosen *pooledKey
+			total  int
+		)
+		for _, k := range p.keys {
+			if !available(k) {
+				continue
+			}
+			k.current += k.Weight
+			total += k.Weight
+			if chosen == nil || k.current > chosen.current {
+				chosen = k
+			}
+		}
+		if chosen != nil {
+			chosen.current -= total
+			return chosen, nil
+		}
+
+	case KeyStrategyPrimary:
+		for _, k := range p.keys {
+			if available(k) {
+				return k, nil
+			}
+		}
+
+	default:
+		for i := range p.keys {
+			k := p.keys[(p.next+i)%len(p.keys)]
+			if available(k) {
+				p.next = (p.next + i + 1) % len(p.keys)
+				return k, nil
+			}
+		}
+	}
+
+	if len(tried) > 0 {
+		return nil, nil
+	}
+	return nil, ErrAllKeysQuarantined
+}
+
+// record records a request sent with the key, quarantining the key if the API rejected it.
+// It reports whether the key was quarantined.
+func (p *keyPool) record(k *pooledKey, err error) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k.requests++
+	if err == nil {
+		return false
+	}
+	k.failures++
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !isKeyError(apiErr.Code) {
+		return false
+	}
+
+	quarantine := p.quarantine
+	if apiErr.RetryAfter > quarantine {
+		quarantine = apiErr.RetryAfter
+	}
+	k.quarantinedUntil = p.now().Add(quarantine)
+	return true
+}
+
+// isKeyError reports whether the error code means the key cannot be used, so another key should be tried.
+func isKeyError(code ErrorCode) bool {
+	return code == ErrQuotaExceeded || code == ErrInvalidKey || code == ErrSuspendedKey
+}
+
+// stats returns the usage of every key.
+func (p *keyPool) stats() []KeyStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	stats := make([]KeyStats, 0, len(p.keys))
+	for _, k := range p.keys {
+		s := KeyStats{Name: k.Name, Requests: k.requests, Failures: k.failures}
+		if now.Before(k.quarantinedUntil) {
+			s.QuarantinedUntil = k.quarantinedUntil
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
@@ -0,0 +1,212 @@
+package what3words
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyPool_Acquire(t *testing.T) {
+	tests := map[string]struct {
+		pool        KeyPool
+		quarantined []string
+		expected    []string
+	}{
+		"round robin": {
+			pool:     KeyPool{Keys: []APIKey{{Key: "a", Name: "a"}, {Key: "b", Name: "b"}, {Key: "c", Name: "c"}}},
+			expected: []string{"a", "b", "c", "a", "b", "c"},
+		},
+		"round robin skips quarantined keys": {
+			pool:        KeyPool{Keys: []APIKey{{Key: "a", Name: "a"}, {Key: "b", Name: "b"}, {Key: "c", Name: "c"}}},
+			quarantined: []string{"b"},
+			expected:    []string{"a", "c", "a", "c"},
+		},
+		"weighted": {
+			pool: KeyPool{
+				Strategy: KeyStrategyWeighted,
+				Keys:     []APIKey{{Key: "a", Name: "a", Weight: 3}, {Key: "b", Name: "b"}},
+			},
+			expected: []string{"a", "a", "b", "a", "a", "a", "b", "a"},
+		},
+		"weighted skips quarantined keys": {
+			pool: KeyPool{
+				Strategy: KeyStrategyWeighted,
+				Keys:     []APIKey{{Key: "a", Name: "a", Weight: 3}, {Key: "b", Name: "b"}},
+			},
+			quarantined: []string{"a"},
+			expected:    []string{"b", "b", "b"},
+		},
+		"primary": {
+			pool:     KeyPool{Strategy: KeyStrategyPrimary, Keys: []APIKey{{Key: "a", Name: "a"}, {Key: "b", Name: "b"}}},
+			expected: []string{"a", "a", "a"},
+		},
+		"secondary while primary is quarantined": {
+			pool:        KeyPool{Strategy: KeyStrategyPrimary, Keys: []APIKey{{Key: "a", Name: "a"}, {Key: "b", Name: "b"}}},
+			quarantined: []string{"a"},
+			expected:    []string{"b", "b", "b"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := newKeyPool(tt.pool)
+			for _, k := range p.keys {
+				for _, q := range tt.quarantined {
+					if k.Name == q {
+						p.record(k, &APIError{StatusCode: http.StatusPaymentRequired, Code: ErrQuotaExceeded})
+					}
+				}
+			}
+
+			var got []string
+			for range tt.expected {
+				k, err := p.acquire(nil)
+				assert.NoError(t, err)
+				got = append(got, k.Name)
+			}
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestKeyPool_Quarantine(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := newKeyPool(KeyPool{Keys: []APIKey{{Key: "secret-key-1"}, {Key: "secret-key-2", Name: "secondary"}}, Quarantine: time.Minute})
+	p.now = func() time.Time { return now }
+
+	first, second := p.keys[0], p.keys[1]
+	assert.Equal(t, "****ey-1", first.Name, "unnamed keys are masked")
+
+	assert.False(t, p.record(first, &APIError{StatusCode: http.StatusBadRequest, Code: ErrBadWords}), "input errors do not quarantine")
+	assert.True(t, p.record(first, &APIError{StatusCode: http.StatusUnauthorized, Code: ErrInvalidKey}))
+	assert.True(t, p.record(second, &APIError{StatusCode: http.StatusPaymentRequired, Code: ErrQuotaExceeded, RetryAfter: time.Hour}))
+
+	_, err := p.acquire(nil)
+	assert.ErrorIs(t, err, ErrAllKeysQuarantined)
+	assert.Equal(t, []KeyStats{
+		{Name: "****ey-1", Requests: 2, Failures: 2, QuarantinedUntil: now.Add(time.Minute)},
+		{Name: "secondary", Requests: 1, Failures: 1, QuarantinedUntil: now.Add(time.Hour)},
+	}, p.stats())
+
+	// Quarantine ends after the configured duration, or the Retry-After of the error if it is longer.
+	now = now.Add(time.Minute)
+	k, err := p.acquire(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, first, k)
+	k, err = p.acquire(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, first, k)
+}
+
+func TestW3w_WithAPIKeys(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		keysUsed []string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-Api-Key")
+		mu.Lock()
+		keysUsed = append(keysUsed, key)
+		mu.Unlock()
+
+		switch key {
+		case "over-quota":
+			rw.WriteHeader(http.StatusPaymentRequired)
+			_, _ = rw.Write([]byte(`{"error": {"code": "QuotaExceeded", "message": "Quota Exceeded"}}`))
+		case "invalid":
+			rw.WriteHeader(http.StatusUnauthorized)
+			_, _ = rw.Write([]byte(`{"error": {"code": "InvalidKey", "message": "Authentication failed; invalid API key"}}`))
+		default:
+			_, _ = rw.Write([]byte(`{"words": "filled.count.soap", "language": "en"}`))
+		}
+	}))
+	defer ts.Close()
+
+	endpoint, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("fails over to the next key", func(t *testing.T) {
+		keysUsed = nil
+		w := NewClient("", WithEndpoint(endpoint), WithAPIKeys(KeyPool{
+			Strategy: KeyStrategyPrimary,
+			Keys: []APIKey{
+				{Key: "over-quota", Name: "business"},
+				{Key: "invalid", Name: "legacy"},
+				{Key: "valid", Name: "fallback"},
+			},
+		}))
+
+		var served string
+		resp, err := w.ConvertTo3wa(ctx, &Coordinates{Lat: 51.520847, Lng: -0.195521}, WithServedKey(&served))
+		assert.NoError(t, err)
+		assert.Equal(t, "filled.count.soap", resp.Words)
+		assert.Equal(t, "fallback", served)
+		assert.Equal(t, []string{"over-quota", "invalid", "valid"}, keysUsed)
+
+		// Quarantined keys are skipped by later requests.
+		_, err = w.ConvertTo3wa(ctx, &Coordinates{Lat: 51.520847, Lng: -0.195521}, WithServedKey(&served))
+		assert.NoError(t, err)
+		assert.Equal(t, "fallback", served)
+		assert.Equal(t, []string{"over-quota", "invalid", "valid", "valid"}, keysUsed)
+
+		stats := w.KeyStats()
+		if assert.Len(t, stats, 3) {
+			assert.Equal(t, KeyStats{Name: "fallback", Requests: 2}, stats[2])
+			assert.Equal(t, uint64(1), stats[0].Failures)
+			assert.False(t, stats[0].QuarantinedUntil.IsZero())
+			assert.False(t, stats[1].QuarantinedUntil.IsZero())
+		}
+	})
+
+	t.Run("fails once every key is rejected", func(t *testing.T) {
+		keysUsed = nil
+		w := NewClient("", WithEndpoint(endpoint), WithRetry(RetryPolicy{MaxRetries: 3}), WithAPIKeys(KeyPool{
+			Keys: []APIKey{{Key: "over-quota"}, {Key: "invalid"}},
+		}))
+
+		_, err := w.ConvertTo3wa(ctx, &Coordinates{Lat: 51.520847, Lng: -0.195521})
+		assert.ErrorIs(t, err, ErrInvalidKey)
+		assert.Equal(t, []string{"over-quota", "invalid"}, keysUsed)
+
+		_, err = w.ConvertTo3wa(ctx, &Coordinates{Lat: 51.520847, Lng: -0.195521})
+		assert.ErrorIs(t, err, ErrAllKeysQuarantined)
+		assert.Len(t, keysUsed, 2, "no request is sent while every key is quarantined")
+	})
+
+	t.Run("single key clients have no key stats", func(t *testing.T) {
+		w := NewClient("valid", WithEndpoint(endpoint))
+
+		var served string
+		_, err := w.ConvertToCoordinates(ctx, "filled.count.soap", WithServedKey(&served))
+		assert.NoError(t, err)
+		assert.Equal(t, "****alid", served)
+		assert.Nil(t, w.KeyStats())
+	})
+}
+
+func TestW3w_WithAPIKeysEmpty(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer ts.Close()
+
+	endpoint, err := url.Parse(ts.URL + "/v3")
+	assert.NoError(t, err)
+
+	w := NewClient("", WithEndpoint(endpoint), WithAPIKeys(KeyPool{}))
+	_, err = w.ConvertTo3wa(context.Background(), &Coordinates{Lat: 51.520847, Lng: -0.195521})
+	assert.ErrorIs(t, err, ErrNoKeysConfigured)
+	assert.NotErrorIs(t, err, ErrAllKeysQuarantined)
+	assert.ErrorIs(t, w.Health(context.Background()), ErrNoKeysConfigured)
+	assert.Zero(t, requests)
+
+	_, err = newKeyPool(KeyPool{}).acquire(nil)
+	assert.ErrorIs(t, err, ErrNoKeysConfigured)
+}
@@ -0,0 +1,125 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// CSV reads CSV records with a header row from r and writes them to w with the converted columns appended.
+// Records which cannot be converted are written with the reason in the error column.
+func (p *Pipeline) CSV(ctx context.Context, r io.Reader, w io.Writer) error {
+	reader := csv.NewReader(r)
+	reader.Comma = p.config.Comma
+	reader.FieldsPerRecord = -1
+
+	writer := csv.NewWriter(w)
+	writer.Comma = p.config.Comma
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("reading CSV header: %w", err)
+	}
+
+	columns, err := p.columnIndexes(header)
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Write(append(header, appendedColumns...)); err != nil {
+		return fmt.Errorf("writing CSV header: %w", err)
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("writing CSV header: %w", err)
+	}
+
+	records := make([][]string, 0, p.config.BatchSize)
+	inputs := make([]input, 0, p.config.BatchSize)
+	for {
+		record, err := reader.Read()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("reading CSV record: %w", err)
+		}
+
+		if record != nil {
+			records = append(records, record)
+			inputs = append(inputs, p.csvInput(record, columns))
+		}
+
+		if len(records) == p.config.BatchSize || (errors.Is(err, io.EOF) && len(records) > 0) {
+			if err := p.writeCSV(ctx, writer, records, inputs); err != nil {
+				return err
+			}
+			records, inputs = records[:0], inputs[:0]
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+	}
+}
+
+// columnIndexes returns the indexes of the configured columns in the header.
+func (p *Pipeline) columnIndexes(header []string) ([]int, error) {
+	names := []string{p.config.WordsColumn}
+	if p.config.WordsColumn == "" {
+		names = []string{p.config.LatColumn, p.config.LngColumn}
+	}
+
+	indexes := make([]int, 0, len(names))
+	for _, name := range names {
+		index := -1
+		for i, column := range header {
+			if column == name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("column %q not found in CSV header", name)
+		}
+		indexes = append(indexes, index)
+	}
+
+	return indexes, nil
+}
+
+// csvInput extracts the value to convert from a record using the column indexes.
+func (p *Pipeline) csvInput(record []string, columns []int) input {
+	for _, column := range columns {
+		if column >= len(record) {
+			return input{err: errors.New("record is missing columns")}
+		}
+	}
+
+	if p.config.WordsColumn != "" {
+		return input{words: record[columns[0]]}
+	}
+
+	coordinates, err := parseCoordinates(record[columns[0]], record[columns[1]])
+	return input{coordinates: coordinates, err: err}
+}
+
+// writeCSV converts a batch of records and writes them with the converted columns appended.
+func (p *Pipeline) writeCSV(ctx context.Context, writer *csv.Writer, records [][]string, inputs []input) error {
+	results, err := p.convert(ctx, inputs)
+	if err != nil {
+		return fmt.Errorf("converting CSV records: %w", err)
+	}
+
+	for i, record := range records {
+		if err := writer.Write(append(record, results[i].values()...)); err != nil {
+			return fmt.Errorf("writing CSV record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("writing CSV records: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package pipeline
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// JSONL reads JSON Lines records from r and writes them to w with the converted fields appended to each object.
+// The original fields are written unchanged and in their original order. Lines which are not JSON objects are
+// written as an object containing the original line as a string in the input field, along with the error.
+// Blank lines are skipped.
+func (p *Pipeline) JSONL(ctx context.Context, r io.Reader, w io.Writer) error {
+	reader := bufio.NewReader(r)
+	writer := bufio.NewWriter(w)
+
+	lines := make([][]byte, 0, p.config.BatchSize)
+	inputs := make([]input, 0, p.config.BatchSize)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("reading JSON line: %w", err)
+		}
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			lines = append(lines, line)
+			inputs = append(inputs, p.jsonInput(line))
+		}
+
+		if len(lines) == p.config.BatchSize || (errors.Is(err, io.EOF) && len(lines) > 0) {
+			if err := p.writeJSONL(ctx, writer, lines, inputs); err != nil {
+				return err
+			}
+			lines, inputs = lines[:0], inputs[:0]
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+	}
+}
+
+// jsonInput extracts the value to convert from a JSON object.
+func (p *Pipeline) jsonInput(line []byte) input {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return input{err: errors.New("line is not a JSON object")}
+	}
+
+	if p.config.WordsColumn != "" {
+		value, ok := fields[p.config.WordsColumn]
+		if !ok {
+			return input{err: fmt.Errorf("field %q not found", p.config.WordsColumn)}
+		}
+
+		var words string
+		if err := json.Unmarshal(value, &words); err != nil {
+			return input{err: fmt.Errorf("field %q must be a string", p.config.WordsColumn)}
+		}
+		return input{words: words}
+	}
+
+	lat, err := jsonNumber(fields, p.config.LatColumn)
+	if err != nil {
+		return input{err: err}
+	}
+
+	lng, err := jsonNumber(fields, p.config.LngColumn)
+	if err != nil {
+		return input{err: err}
+	}
+
+	coordinates, err := parseCoordinates(lat, lng)
+	return input{coordinates: coordinates, err: err}
+}
+
+// jsonNumber returns the value of a field given either as a JSON number or as a string.
+func jsonNumber(fields map[string]json.RawMessage, name string) (string, error) {
+	value, ok := fields[name]
+	if !ok {
+		return "", fmt.Errorf("field %q not found", name)
+	}
+
+	var number json.Number
+	if err := json.Unmarshal(value, &number); err == nil {
+		return number.String(), nil
+	}
+
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return s, nil
+	}
+
+	return "", fmt.Errorf("field %q must be a number", name)
+}
+
+// writeJSONL converts a batch of lines and writes them with the converted fields appended.
+func (p *Pipeline) writeJSONL(ctx context.Context, writer *bufio.Writer, lines [][]byte, inputs []input) error {
+	results, err := p.convert(ctx, inputs)
+	if err != nil {
+		return fmt.Errorf("converting JSON lines: %w", err)
+	}
+
+	for i, line := range lines {
+		if _, err := writer.Write(appendFields(line, results[i])); err != nil {
+			return fmt.Errorf("writing JSON line: %w", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("writing JSON lines: %w", err)
+	}
+
+	return nil
+}
+
+// appendFields appends the converted fields to the JSON object in line, leaving the original fields untouched.
+func appendFields(line []byte, r result) []byte {
+	var out []byte
+	if len(line) >= 2 && line[0] == '{' && line[len(line)-1] == '}' && json.Valid(line) {
+		out = append(out, bytes.TrimSpace(line[:len(line)-1])...)
+		if len(bytes.TrimSpace(out[1:])) > 0 {
+			out = append(out, ',')
+		}
+	} else {
+		quoted, _ := json.Marshal(string(line))
+		out = append(out, `{"input":`...)
+		out = append(out, quoted...)
+		out = append(out, ',')
+	}
+
+	values := r.values()
+	for i, column := range appendedColumns {
+		if i > 0 {
+			out = append(out, ',')
+		}
+		out = strconv.AppendQuote(out, column)
+		out = append(out, ':')
+
+		switch {
+		case values[i] == "":
+			out = append(out, "null"...)
+		case column == ColumnLat || column == ColumnLng:
+			out = append(out, values[i]...)
+		default:
+			quoted, _ := json.Marshal(values[i])
+			out = append(out, quoted...)
+		}
+	}
+
+	return append(out, '}', '\n')
+}
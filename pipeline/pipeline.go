@@ -0,0 +1,175 @@
+// Package pipeline enriches streams of CSV or JSON Lines records with what3words conversions.
+//
+// Records are read from an io.Reader, converted in fixed size batches and written to an io.Writer in input order,
+// so memory use does not grow with the size of the input. Every original column or field is preserved and the
+// words, lat, lng, country, nearestPlace and error columns are appended to each record. If the input already has
+// columns with these names, the appended columns follow them, so JSON decoders which keep the last duplicate key
+// see the converted values.
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+const (
+	_defaultBatchSize   = 100
+	_defaultConcurrency = 10
+)
+
+// Columns appended to every record.
+const (
+	ColumnWords        = "words"
+	ColumnLat          = "lat"
+	ColumnLng          = "lng"
+	ColumnCountry      = "country"
+	ColumnNearestPlace = "nearestPlace"
+	ColumnError        = "error"
+)
+
+// appendedColumns are the names of the columns appended to every record, in order.
+var appendedColumns = []string{ColumnWords, ColumnLat, ColumnLng, ColumnCountry, ColumnNearestPlace, ColumnError}
+
+// Config configures which columns are converted and how many requests are made at once.
+// Either WordsColumn, or both LatColumn and LngColumn, must be set.
+type Config struct {
+	// LatColumn and LngColumn name the CSV columns or JSON fields containing coordinates to convert
+	// to 3 word addresses.
+	LatColumn string
+	LngColumn string
+
+	// WordsColumn names the CSV column or JSON field containing 3 word addresses to convert to coordinates.
+	WordsColumn string
+
+	// BatchSize is the number of records read before they are converted and written. Defaults to 100.
+	BatchSize int
+
+	// Concurrency is the maximum number of requests in flight at once. Defaults to 10.
+	Concurrency int
+
+	// Comma is the CSV field delimiter. Defaults to ','.
+	Comma rune
+}
+
+// Pipeline converts streams of records using a what3words client.
+type Pipeline struct {
+	client what3words.What3Words
+	config Config
+}
+
+// New creates a Pipeline which converts records with the client according to the config.
+func New(client what3words.What3Words, config Config) (*Pipeline, error) {
+	switch {
+	case config.WordsColumn != "" && (config.LatColumn != "" || config.LngColumn != ""):
+		return nil, errors.New("only one of words column or lat and lng columns can be set")
+	case config.WordsColumn == "" && (config.LatColumn == "" || config.LngColumn == ""):
+		return nil, errors.New("either words column or both lat and lng columns must be set")
+	}
+
+	if config.BatchSize <= 0 {
+		config.BatchSize = _defaultBatchSize
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = _defaultConcurrency
+	}
+	if config.Comma == 0 {
+		config.Comma = ','
+	}
+
+	return &Pipeline{client: client, config: config}, nil
+}
+
+// input is the value to convert from a single record. err is set if the record could not be parsed.
+type input struct {
+	words       string
+	coordinates what3words.Coordinates
+	err         error
+}
+
+// result is the outcome of converting a single record.
+type result struct {
+	location *what3words.LocationResponse
+	err      error
+}
+
+// values returns the appended column values for the result.
+func (r result) values() []string {
+	if r.err != nil {
+		return []string{"", "", "", "", "", r.err.Error()}
+	}
+
+	return []string{
+		r.location.Words,
+		strconv.FormatFloat(r.location.Coordinates.Lat, 'f', -1, 64),
+		strconv.FormatFloat(r.location.Coordinates.Lng, 'f', -1, 64),
+		r.location.Country,
+		r.location.NearestPlace,
+		"",
+	}
+}
+
+// parseCoordinates parses the latitude and longitude of a record.
+func parseCoordinates(lat, lng string) (what3words.Coordinates, error) {
+	latitude, err := strconv.ParseFloat(lat, 64)
+	if err != nil {
+		return what3words.Coordinates{}, errors.New("invalid latitude " + strconv.Quote(lat))
+	}
+
+	longitude, err := strconv.ParseFloat(lng, 64)
+	if err != nil {
+		return what3words.Coordinates{}, errors.New("invalid longitude " + strconv.Quote(lng))
+	}
+
+	return what3words.Coordinates{Lat: latitude, Lng: longitude}, nil
+}
+
+// convert converts a batch of inputs, returning one result per input in input order.
+// Inputs which failed to parse are not sent and keep their parse error.
+func (p *Pipeline) convert(ctx context.Context, inputs []input) ([]result, error) {
+	results := make([]result, len(inputs))
+
+	var indexes []int
+	for i, in := range inputs {
+		if in.err != nil {
+			results[i].err = in.err
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+
+	if len(indexes) == 0 {
+		return results, nil
+	}
+
+	opts := what3words.BatchOptions{Concurrency: p.config.Concurrency}
+
+	var (
+		batch []what3words.BatchResult
+		err   error
+	)
+	if p.config.WordsColumn != "" {
+		words := make([]string, 0, len(indexes))
+		for _, i := range indexes {
+			words = append(words, inputs[i].words)
+		}
+		batch, err = p.client.BatchConvertToCoordinates(ctx, words, opts)
+	} else {
+		coordinates := make([]what3words.Coordinates, 0, len(indexes))
+		for _, i := range indexes {
+			coordinates = append(coordinates, inputs[i].coordinates)
+		}
+		batch, err = p.client.BatchConvertTo3wa(ctx, coordinates, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for j, i := range indexes {
+		results[i] = result{location: batch[j].Response, err: batch[j].Err}
+	}
+
+	return results, nil
+}
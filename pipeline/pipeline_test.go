@@ -0,0 +1,180 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+func newTestClient(t *testing.T) (what3words.What3Words, func()) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		var err error
+		switch {
+		case query.Get("words") == "bad.words.here":
+			rw.WriteHeader(http.StatusBadRequest)
+			_, err = rw.Write([]byte(`{"error": {"code": "BadWords", "message": "invalid words"}}`))
+		case r.URL.Path == "/convert-to-coordinates":
+			_, err = fmt.Fprintf(rw, `{
+				"country": "GB",
+				"nearestPlace": "Bayswater, London",
+				"coordinates": {"lng": -0.195521, "lat": 51.520847},
+				"words": %q,
+				"language": "en"
+			}`, query.Get("words"))
+		case r.URL.Path == "/convert-to-3wa":
+			_, err = rw.Write([]byte(`{
+				"country": "GB",
+				"nearestPlace": "Bayswater, London",
+				"coordinates": {"lng": -0.195521, "lat": 51.520847},
+				"words": "filled.count.soap",
+				"language": "en"
+			}`))
+		}
+		assert.NoError(t, err)
+	}))
+
+	u, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	return what3words.NewClient("example-api-key", what3words.WithEndpoint(u)), ts.Close
+}
+
+func TestNew(t *testing.T) {
+	tests := map[string]struct {
+		config        Config
+		expectedError string
+	}{
+		"words column":            {config: Config{WordsColumn: "w3w"}},
+		"lat and lng columns":     {config: Config{LatColumn: "lat", LngColumn: "lng"}},
+		"no columns":              {config: Config{}, expectedError: "either words column or both lat and lng columns must be set"},
+		"only lat column":         {config: Config{LatColumn: "lat"}, expectedError: "either words column or both lat and lng columns must be set"},
+		"words and lat and lng":   {config: Config{WordsColumn: "w3w", LatColumn: "lat", LngColumn: "lng"}, expectedError: "only one of words column or lat and lng columns can be set"},
+		"words and lat column":    {config: Config{WordsColumn: "w3w", LatColumn: "lat"}, expectedError: "only one of words column or lat and lng columns can be set"},
+		"defaults batch settings": {config: Config{WordsColumn: "w3w", BatchSize: -1, Concurrency: -1}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := New(nil, tt.config)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Greater(t, got.config.BatchSize, 0)
+				assert.Greater(t, got.config.Concurrency, 0)
+			}
+		})
+	}
+}
+
+func TestPipeline_CSV(t *testing.T) {
+	client, closeServer := newTestClient(t)
+	defer closeServer()
+
+	tests := map[string]struct {
+		config        Config
+		input         string
+		expected      string
+		expectedError string
+	}{
+		"convert words to coordinates": {
+			config: Config{WordsColumn: "address", BatchSize: 2},
+			input: "id,address,note\n" +
+				"1,filled.count.soap,front door\n" +
+				"2,bad.words.here,\n" +
+				"3,index.home.raft,\"back, gate\"\n",
+			expected: "id,address,note,words,lat,lng,country,nearestPlace,error\n" +
+				"1,filled.count.soap,front door,filled.count.soap,51.520847,-0.195521,GB,\"Bayswater, London\",\n" +
+				"2,bad.words.here,,,,,,,converting w3w to coordinates: convert-to-coordinates returned status 400: BadWords: invalid words\n" +
+				"3,index.home.raft,\"back, gate\",index.home.raft,51.520847,-0.195521,GB,\"Bayswater, London\",\n",
+		},
+		"convert coordinates to words": {
+			config: Config{LatColumn: "latitude", LngColumn: "longitude", Comma: ';'},
+			input: "latitude;longitude\n" +
+				"51.520847;-0.195521\n" +
+				"north;-0.195521\n" +
+				"51.520847\n",
+			expected: "latitude;longitude;words;lat;lng;country;nearestPlace;error\n" +
+				"51.520847;-0.195521;filled.count.soap;51.520847;-0.195521;GB;Bayswater, London;\n" +
+				"north;-0.195521;;;;;;\"invalid latitude \"\"north\"\"\"\n" +
+				"51.520847;;;;;;record is missing columns\n",
+		},
+		"missing column": {
+			config:        Config{WordsColumn: "w3w"},
+			input:         "id,address\n1,filled.count.soap\n",
+			expectedError: `column "w3w" not found in CSV header`,
+		},
+		"header only": {
+			config:   Config{WordsColumn: "address"},
+			input:    "id,address\n",
+			expected: "id,address,words,lat,lng,country,nearestPlace,error\n",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := New(client, tt.config)
+			assert.NoError(t, err)
+
+			var out bytes.Buffer
+			err = p.CSV(context.Background(), strings.NewReader(tt.input), &out)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestPipeline_JSONL(t *testing.T) {
+	client, closeServer := newTestClient(t)
+	defer closeServer()
+
+	tests := map[string]struct {
+		config   Config
+		input    string
+		expected string
+	}{
+		"convert words to coordinates": {
+			config: Config{WordsColumn: "address"},
+			input: `{"id": 1, "address": "filled.count.soap", "tags": ["a", "b"]}` + "\n" +
+				"\n" +
+				`{"id": 2, "address": "bad.words.here"}` + "\n" +
+				`{"id": 3}` + "\n" +
+				`not json`,
+			expected: `{"id": 1, "address": "filled.count.soap", "tags": ["a", "b"],"words":"filled.count.soap","lat":51.520847,"lng":-0.195521,"country":"GB","nearestPlace":"Bayswater, London","error":null}` + "\n" +
+				`{"id": 2, "address": "bad.words.here","words":null,"lat":null,"lng":null,"country":null,"nearestPlace":null,"error":"converting w3w to coordinates: convert-to-coordinates returned status 400: BadWords: invalid words"}` + "\n" +
+				`{"id": 3,"words":null,"lat":null,"lng":null,"country":null,"nearestPlace":null,"error":"field \"address\" not found"}` + "\n" +
+				`{"input":"not json","words":null,"lat":null,"lng":null,"country":null,"nearestPlace":null,"error":"line is not a JSON object"}` + "\n",
+		},
+		"convert coordinates to words": {
+			config: Config{LatColumn: "lat", LngColumn: "lng", BatchSize: 1},
+			input: `{"lat": 51.520847, "lng": "-0.195521"}` + "\n" +
+				`{}` + "\n",
+			expected: `{"lat": 51.520847, "lng": "-0.195521","words":"filled.count.soap","lat":51.520847,"lng":-0.195521,"country":"GB","nearestPlace":"Bayswater, London","error":null}` + "\n" +
+				`{"words":null,"lat":null,"lng":null,"country":null,"nearestPlace":null,"error":"field \"lat\" not found"}` + "\n",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := New(client, tt.config)
+			assert.NoError(t, err)
+
+			var out bytes.Buffer
+			err = p.JSONL(context.Background(), strings.NewReader(tt.input), &out)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, out.String())
+		})
+	}
+}
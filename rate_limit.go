@@ -0,0 +1,202 @@
+package what3words
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned, wrapped in a RateLimitError, when a non-blocking RateLimiter has no tokens available.
+var ErrRateLimited = errors.New("rate limited locally")
+
+// RateLimitError is returned when a request is rejected by a non-blocking RateLimiter before being sent.
+type RateLimitError struct {
+	// Endpoint is the API endpoint the request was for, e.g. autosuggest.
+	Endpoint string
+
+	// RetryAfter is how long until a token becomes available for the endpoint.
+	RetryAfter time.Duration
+}
+
+// Error returns a description of the rate limited request.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s: %s, retry after %s", e.Endpoint, ErrRateLimited, e.RetryAfter)
+}
+
+// Unwrap returns ErrRateLimited, allowing errors.Is to match a RateLimitError.
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// Limit is a request budget for a what3words plan, enforced with token buckets.
+type Limit struct {
+	// PerSecond is the sustained number of requests allowed per second. Zero means no per second limit.
+	PerSecond float64
+
+	// Burst is the number of requests which can be made at once before PerSecond applies.
+	// Defaults to PerSecond rounded up, with a minimum of 1.
+	Burst int
+
+	// PerDay is the number of requests allowed in any 24 hour period. Zero means no daily limit.
+	PerDay int
+}
+
+// RateLimits configures a RateLimiter.
+type RateLimits struct {
+	// Default is the budget shared by every endpoint which does not have its own entry in Endpoints.
+	Default Limit
+
+	// Endpoints contains separate budgets keyed by endpoint name, e.g. "autosuggest" or "convert-to-3wa".
+	Endpoints map[string]Limit
+
+	// NonBlocking makes requests fail immediately with a RateLimitError instead of waiting for a token.
+	NonBlocking bool
+}
+
+// RateLimiter is a client-side token bucket rate limiter with separate budgets per endpoint.
+// A RateLimiter is safe for concurrent use and can be shared between clients using the same API key.
+type RateLimiter struct {
+	limits RateLimits
+	now    func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string][]*bucket
+}
+
+// NewRateLimiter creates a RateLimiter enforcing the given limits.
+func NewRateLimiter(limits RateLimits) *RateLimiter {
+	return &RateLimiter{
+		limits:  limits,
+		now:     time.Now,
+		buckets: make(map[string][]*bucket),
+	}
+}
+
+// WithRateLimit is a Functional Option for applying a RateLimiter to every request made by the client,
+// including retries.
+func WithRateLimit(limiter *RateLimiter) Option {
+	return func(w *w3w) {
+		w.limiter = limiter
+	}
+}
+
+// Wait blocks until a request to the endpoint is allowed or the context is done.
+// If the limiter is non-blocking, Wait returns a RateLimitError immediately when no token is available.
+func (l *RateLimiter) Wait(ctx context.Context, endpoint string) error {
+	l.mu.Lock()
+	buckets := l.bucketsFor(endpoint)
+	now := l.now()
+
+	delay := reserveDelay(buckets, now)
+	if delay > 0 && l.limits.NonBlocking {
+		l.mu.Unlock()
+		return &RateLimitError{Endpoint: endpoint, RetryAfter: delay}
+	}
+
+	for _, b := range buckets {
+		b.tokens--
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	if wait(ctx, delay) {
+		return nil
+	}
+
+	// Give the reserved tokens back so that the cancelled request does not use up the budget.
+	l.mu.Lock()
+	for _, b := range buckets {
+		b.tokens = math.Min(b.tokens+1, b.burst)
+	}
+	l.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("waiting for rate limiter: %w", ctx.Err())
+	}
+	return fmt.Errorf("waiting for rate limiter: %w", context.DeadlineExceeded)
+}
+
+// Allow reports whether a request to the endpoint can be made now, consuming a token if so.
+func (l *RateLimiter) Allow(endpoint string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	buckets := l.bucketsFor(endpoint)
+	if reserveDelay(buckets, l.now()) > 0 {
+		return false
+	}
+
+	for _, b := range buckets {
+		b.tokens--
+	}
+	return true
+}
+
+// bucketsFor returns the token buckets for an endpoint, creating them on first use. Must be called with l.mu held.
+func (l *RateLimiter) bucketsFor(endpoint string) []*bucket {
+	limit, ok := l.limits.Endpoints[endpoint]
+	if !ok {
+		endpoint, limit = "", l.limits.Default
+	}
+
+	buckets, ok := l.buckets[endpoint]
+	if !ok {
+		buckets = newBuckets(limit, l.now())
+		l.buckets[endpoint] = buckets
+	}
+
+	return buckets
+}
+
+// bucket is a token bucket refilled continuously at rate tokens per second up to burst tokens.
+// tokens may become negative when requests have reserved tokens which are not yet available.
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBuckets(limit Limit, now time.Time) []*bucket {
+	var buckets []*bucket
+
+	if limit.PerSecond > 0 {
+		burst := float64(limit.Burst)
+		if burst <= 0 {
+			burst = math.Max(1, math.Ceil(limit.PerSecond))
+		}
+		buckets = append(buckets, &bucket{rate: limit.PerSecond, burst: burst, tokens: burst, last: now})
+	}
+
+	if limit.PerDay > 0 {
+		perDay := float64(limit.PerDay)
+		buckets = append(buckets, &bucket{rate: perDay / (24 * time.Hour).Seconds(), burst: perDay, tokens: perDay, last: now})
+	}
+
+	return buckets
+}
+
+// reserveDelay refills the buckets and returns how long until every bucket has a token available.
+func reserveDelay(buckets []*bucket, now time.Time) time.Duration {
+	var delay time.Duration
+	for _, b := range buckets {
+		if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+			b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+			b.last = now
+		}
+
+		if b.tokens < 1 {
+			d := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+			if d > delay {
+				delay = d
+			}
+		}
+	}
+	return delay
+}
@@ -0,0 +1,150 @@
+package what3words
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	tests := map[string]struct {
+		limits   RateLimits
+		requests []string
+		advance  time.Duration
+		expected []bool
+	}{
+		"burst is allowed then limited": {
+			limits:   RateLimits{Default: Limit{PerSecond: 2}},
+			requests: []string{"convert-to-3wa", "convert-to-3wa", "convert-to-3wa"},
+			expected: []bool{true, true, false},
+		},
+		"endpoints without their own limit share the default budget": {
+			limits:   RateLimits{Default: Limit{PerSecond: 1}},
+			requests: []string{"convert-to-3wa", "convert-to-coordinates"},
+			expected: []bool{true, false},
+		},
+		"endpoints with their own limit have separate budgets": {
+			limits: RateLimits{
+				Default:   Limit{PerSecond: 1},
+				Endpoints: map[string]Limit{"autosuggest": {PerSecond: 1}},
+			},
+			requests: []string{"autosuggest", "convert-to-3wa", "autosuggest", "convert-to-3wa"},
+			expected: []bool{true, true, false, false},
+		},
+		"tokens are refilled over time": {
+			limits:   RateLimits{Default: Limit{PerSecond: 1}},
+			requests: []string{"grid-section", "grid-section", "grid-section"},
+			advance:  time.Second,
+			expected: []bool{true, true, true},
+		},
+		"daily limit applies after per second limit refills": {
+			limits:   RateLimits{Default: Limit{PerSecond: 10, PerDay: 2}},
+			requests: []string{"convert-to-3wa", "convert-to-3wa", "convert-to-3wa"},
+			advance:  time.Second,
+			expected: []bool{true, true, false},
+		},
+		"no limits configured": {
+			limits:   RateLimits{},
+			requests: []string{"convert-to-3wa", "convert-to-3wa", "convert-to-3wa"},
+			expected: []bool{true, true, true},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			now := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+			limiter := NewRateLimiter(tt.limits)
+			limiter.now = func() time.Time { return now }
+
+			got := make([]bool, 0, len(tt.requests))
+			for _, endpoint := range tt.requests {
+				got = append(got, limiter.Allow(endpoint))
+				now = now.Add(tt.advance)
+			}
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	t.Run("blocks until a token is available", func(t *testing.T) {
+		limiter := NewRateLimiter(RateLimits{Default: Limit{PerSecond: 20, Burst: 1}})
+
+		start := time.Now()
+		assert.NoError(t, limiter.Wait(context.Background(), "autosuggest"))
+		assert.NoError(t, limiter.Wait(context.Background(), "autosuggest"))
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+
+	t.Run("returns when the context is cancelled", func(t *testing.T) {
+		limiter := NewRateLimiter(RateLimits{Default: Limit{PerSecond: 0.001}})
+		assert.NoError(t, limiter.Wait(context.Background(), "autosuggest"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := limiter.Wait(ctx, "autosuggest")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("non blocking mode fails fast", func(t *testing.T) {
+		limiter := NewRateLimiter(RateLimits{Default: Limit{PerSecond: 1}, NonBlocking: true})
+		assert.NoError(t, limiter.Wait(context.Background(), "autosuggest"))
+
+		err := limiter.Wait(context.Background(), "autosuggest")
+		assert.ErrorIs(t, err, ErrRateLimited)
+
+		var rateLimitErr *RateLimitError
+		assert.True(t, errors.As(err, &rateLimitErr))
+		assert.Equal(t, "autosuggest", rateLimitErr.Endpoint)
+		assert.Greater(t, rateLimitErr.RetryAfter, time.Duration(0))
+	})
+
+	t.Run("budget is shared between goroutines", func(t *testing.T) {
+		limiter := NewRateLimiter(RateLimits{Default: Limit{PerSecond: 0.001, Burst: 5}})
+
+		var allowed int32
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if limiter.Allow("convert-to-3wa") {
+					atomic.AddInt32(&allowed, 1)
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(5), allowed)
+	})
+}
+
+func TestW3w_RateLimit(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, err := rw.Write([]byte(`{"languages": []}`))
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	limiter := NewRateLimiter(RateLimits{Default: Limit{PerSecond: 1}, NonBlocking: true})
+	w := NewClient("example-api-key", WithEndpoint(u), WithRateLimit(limiter), WithRetry(RetryPolicy{MaxRetries: 3}))
+
+	_, err = w.AvailableLanguages(context.Background())
+	assert.NoError(t, err)
+
+	_, err = w.AvailableLanguages(context.Background())
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.ErrorContains(t, err, "retrieving available languages")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
@@ -1,34 +1,213 @@
 package what3words
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
 )
 
-func (w *w3w) request(ctx context.Context, url string, out interface{}) error {
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// response is the body of a successful response and the name of the API key which served it.
+type response struct {
+	body []byte
+	key  string
+}
+
+func (w *w3w) request(ctx context.Context, u *url.URL, c *call, out interface{}) error {
+	if w.configErr != nil {
+		return w.configErr
+	}
+
+	resp, err := w.fetch(ctx, u, c)
 	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
+		return err
 	}
 
-	request.Header.Set("Content-Type", "application/json")
-	request.Header.Set("X-Api-Key", w.apiKey)
+	if c.servedKey != nil {
+		*c.servedKey = resp.key
+	}
+	if c.body != nil {
+		*c.body = append([]byte(nil), resp.body...)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if decoder, ok := out.(geoJSONDecoder); ok && c.format == FormatGeoJSON {
+		if err := decoder.decodeGeoJSON(resp.body); err != nil {
+			return fmt.Errorf("decoding GeoJSON response body into output: %w", err)
+		}
+		return nil
+	}
+
+	if err := json.NewDecoder(bytes.NewReader(resp.body)).Decode(out); err != nil {
+		return fmt.Errorf("decoding response body into output: %w", err)
+	}
+
+	return nil
+}
+
+// fetch sends a GET request to the URL and returns the successful response,
+// sharing the request with identical requests in flight if request deduplication is enabled.
+func (w *w3w) fetch(ctx context.Context, u *url.URL, c *call) (response, error) {
+	if w.flights == nil {
+		return w.fetchWithRetry(ctx, u, c)
+	}
+
+	return w.flights.do(ctx, flightKey(u)+c.headerKey(), func(ctx context.Context) (response, error) {
+		return w.fetchWithRetry(ctx, u, c)
+	})
+}
+
+// fetchWithRetry sends a GET request to the URL and returns the successful response,
+// retrying failed attempts if a RetryPolicy has been configured.
+func (w *w3w) fetchWithRetry(ctx context.Context, u *url.URL, c *call) (response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := w.send(ctx, u, c)
+		if err == nil {
+			return resp, nil
+		}
+
+		if w.retry == nil || attempt >= w.retry.MaxRetries {
+			return response{}, err
+		}
+
+		retry, retryAfter := shouldRetry(ctx, err)
+		if !retry {
+			return response{}, err
+		}
+
+		delay := w.retry.Backoff(attempt + 1)
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+
+		if !wait(ctx, delay) {
+			return response{}, err
+		}
+	}
+}
+
+// send makes a single attempt at a GET request to the URL, waiting for the rate limiter if one has been configured.
+func (w *w3w) send(ctx context.Context, u *url.URL, c *call) (response, error) {
+	if w.limiter != nil {
+		if err := w.limiter.Wait(ctx, endpointName(u)); err != nil {
+			return response{}, err
+		}
+	}
+
+	return w.sendWithFailover(ctx, u, c)
+}
+
+// sendWithFailover sends a GET request to the URL with the client's key. If the client has several keys, a key
+// rejected by the API is quarantined and the request is sent again with the next available key.
+func (w *w3w) sendWithFailover(ctx context.Context, u *url.URL, c *call) (response, error) {
+	if w.keys == nil {
+		body, err := w.sendWithKey(ctx, u, c, w.apiKey)
+		return response{body: body, key: maskKey(w.apiKey)}, err
+	}
+
+	tried := make(map[*pooledKey]bool)
+	var lastErr error
+	for {
+		key, err := w.keys.acquire(tried)
+		if err != nil {
+			return response{}, err
+		}
+		if key == nil {
+			return response{}, lastErr
+		}
+		tried[key] = true
+
+		body, err := w.sendWithKey(ctx, u, c, key.Key)
+		if !w.keys.record(key, err) {
+			return response{body: body, key: key.Name}, err
+		}
+		lastErr = err
+	}
+}
+
+// sendWithKey sends a GET request to the URL with the API key and returns the body of a response with a 2xx status.
+func (w *w3w) sendWithKey(ctx context.Context, u *url.URL, c *call, key string) ([]byte, error) {
+	request, err := newRequest(ctx, u, key, c.header)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := w.http.Do(request)
 	if err != nil {
-		return fmt.Errorf("sending HTTP request: %w", err)
+		return nil, fmt.Errorf("sending HTTP request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("request for %s returned unexpected status %s", url, resp.Status)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, newAPIError(resp, endpointName(u))
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
-		return fmt.Errorf("decoding response body into output: %w", err)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
 	}
 
-	return nil
+	return body, nil
+}
+
+// newRequest creates a GET request to the URL with the API key and any extra headers, which replace the headers
+// set by the client. No key is sent if it is empty, as self-hosted deployments may not require one.
+func newRequest(ctx context.Context, u *url.URL, key string, header http.Header) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		request.Header.Set("X-Api-Key", key)
+	}
+	for name, values := range header {
+		request.Header[name] = values
+	}
+
+	return request, nil
+}
+
+// newAPIError builds an APIError from a failed response, decoding the what3words error payload if present.
+func newAPIError(resp *http.Response, endpoint string) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Endpoint:   endpoint,
+		RetryAfter: parseRetryAfter(resp.Header, time.Now()),
+	}
+
+	var body apiErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err == nil {
+		apiErr.Code = ErrorCode(body.Error.Code)
+		apiErr.Message = body.Error.Message
+	}
+
+	return apiErr
+}
+
+// endpointURL returns the URL of the named API endpoint. The name is appended to the path of the client endpoint
+// as is, so path prefixes of self-hosted deployments, trailing slashes and escaped characters are preserved, and any
+// query parameters of the client endpoint are kept.
+func (w *w3w) endpointURL(name string) *url.URL {
+	u := *w.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + name
+	if u.RawPath != "" {
+		u.RawPath = strings.TrimSuffix(u.RawPath, "/") + "/" + name
+	}
+	return &u
+}
+
+// endpointName returns the name of the API endpoint for a request URL, e.g. convert-to-3wa.
+func endpointName(u *url.URL) string {
+	return path.Base(u.Path)
 }
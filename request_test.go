@@ -0,0 +1,102 @@
+package what3words
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestW3w_RequestAPIError(t *testing.T) {
+	type response struct {
+		statusCode int
+		body       []byte
+	}
+
+	tests := map[string]struct {
+		expected      *APIError
+		expectedIs    error
+		expectedError string
+		response      response
+	}{
+		"decodes bad words error": {
+			expected: &APIError{
+				StatusCode: http.StatusBadRequest,
+				Code:       ErrBadWords,
+				Message:    "words must be a valid 3 word address, such as filled.count.soap or ///filled.count.soap",
+				Endpoint:   "convert-to-coordinates",
+			},
+			expectedIs:    ErrBadWords,
+			expectedError: "convert-to-coordinates returned status 400: BadWords: words must be a valid 3 word address",
+			response: response{
+				statusCode: http.StatusBadRequest,
+				body: []byte(`{
+					"error": {
+						"code": "BadWords",
+						"message": "words must be a valid 3 word address, such as filled.count.soap or ///filled.count.soap"
+					}
+				}`),
+			},
+		},
+		"decodes quota exceeded error": {
+			expected: &APIError{
+				StatusCode: http.StatusPaymentRequired,
+				Code:       ErrQuotaExceeded,
+				Message:    "Quota Exceeded. Please upgrade your usage plan, or contact support@what3words.com",
+				Endpoint:   "convert-to-coordinates",
+			},
+			expectedIs:    ErrQuotaExceeded,
+			expectedError: "converting w3w to coordinates",
+			response: response{
+				statusCode: http.StatusPaymentRequired,
+				body: []byte(`{
+					"error": {
+						"code": "QuotaExceeded",
+						"message": "Quota Exceeded. Please upgrade your usage plan, or contact support@what3words.com"
+					}
+				}`),
+			},
+		},
+		"undecodable error body": {
+			expected: &APIError{
+				StatusCode: http.StatusBadGateway,
+				Endpoint:   "convert-to-coordinates",
+			},
+			expectedError: "convert-to-coordinates returned unexpected status 502 Bad Gateway",
+			response: response{
+				statusCode: http.StatusBadGateway,
+				body:       []byte(`<html>bad gateway</html>`),
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(tt.response.statusCode)
+				_, err := rw.Write(tt.response.body)
+				assert.NoError(t, err)
+			}))
+			defer ts.Close()
+
+			u, err := url.Parse(ts.URL)
+			assert.NoError(t, err)
+
+			w := NewClient("example-api-key", WithEndpoint(u))
+			_, err = w.ConvertToCoordinates(context.Background(), "filled.count.soap")
+			assert.ErrorContains(t, err, tt.expectedError)
+
+			var apiErr *APIError
+			assert.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, tt.expected, apiErr)
+
+			if tt.expectedIs != nil {
+				assert.ErrorIs(t, err, tt.expectedIs)
+			}
+			assert.NotErrorIs(t, err, ErrInvalidKey)
+		})
+	}
+}
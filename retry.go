@@ -0,0 +1,118 @@
+package what3words
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	_defaultRetryBaseDelay = 100 * time.Millisecond
+	_defaultRetryMaxDelay  = 10 * time.Second
+)
+
+// BackoffFunc returns how long to wait before retry number attempt, where the first retry is attempt 1.
+type BackoffFunc func(attempt int) time.Duration
+
+// ExponentialBackoff returns a BackoffFunc which doubles the delay from base on each attempt, up to max,
+// and applies full jitter so that concurrent clients do not retry in lock step.
+func ExponentialBackoff(base, max time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		delay := base
+		for i := 1; i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			delay = max
+		}
+		if delay <= 0 {
+			return 0
+		}
+		return time.Duration(rand.Int63n(int64(delay) + 1))
+	}
+}
+
+// ConstantBackoff returns a BackoffFunc which always waits for the given delay.
+func ConstantBackoff(delay time.Duration) BackoffFunc {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// RetryPolicy configures how failed requests are retried.
+// Requests are only retried on network errors, 429 Too Many Requests and 5xx responses.
+// Documented what3words client errors such as BadWords or QuotaExceeded are never retried.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries after the initial attempt.
+	MaxRetries int
+
+	// Backoff determines the delay between attempts. Defaults to ExponentialBackoff(100ms, 10s).
+	// A Retry-After header returned by the API takes precedence when it asks for a longer delay.
+	Backoff BackoffFunc
+}
+
+// WithRetry is a Functional Option for retrying failed requests according to the given RetryPolicy.
+// Retries never wait beyond the deadline of the request context.
+func WithRetry(policy RetryPolicy) Option {
+	return func(w *w3w) {
+		if policy.Backoff == nil {
+			policy.Backoff = ExponentialBackoff(_defaultRetryBaseDelay, _defaultRetryMaxDelay)
+		}
+		w.retry = &policy
+	}
+}
+
+// shouldRetry reports whether a failed attempt can be retried, and the delay requested by the server if any.
+func shouldRetry(ctx context.Context, err error) (bool, time.Duration) {
+	if ctx.Err() != nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrRateLimited) ||
+		errors.Is(err, ErrAllKeysQuarantined) || errors.Is(err, ErrNoKeysConfigured) {
+		return false, 0
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Temporary(), apiErr.RetryAfter
+	}
+
+	// Any other error from sending the request is a transport failure such as a connection reset.
+	return true, 0
+}
+
+// wait sleeps for the given delay, returning false without waiting if the delay would exceed the context deadline
+// or if the context is cancelled while waiting.
+func wait(ctx context.Context, delay time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
@@ -0,0 +1,192 @@
+package what3words
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestW3w_Retry(t *testing.T) {
+	type response struct {
+		statusCode int
+		headers    map[string]string
+		body       []byte
+	}
+
+	success := response{
+		statusCode: http.StatusOK,
+		body:       []byte(`{"languages": [{"code": "en", "name": "English", "nativeName": "English"}]}`),
+	}
+
+	tests := map[string]struct {
+		policy        RetryPolicy
+		timeout       time.Duration
+		responses     []response
+		expectedCalls int32
+		expectedError ErrorCode
+	}{
+		"retries server errors until success": {
+			policy: RetryPolicy{MaxRetries: 3, Backoff: ConstantBackoff(0)},
+			responses: []response{
+				{statusCode: http.StatusServiceUnavailable},
+				{statusCode: http.StatusBadGateway},
+				success,
+			},
+			expectedCalls: 3,
+		},
+		"retries too many requests": {
+			policy: RetryPolicy{MaxRetries: 1, Backoff: ConstantBackoff(0)},
+			responses: []response{
+				{statusCode: http.StatusTooManyRequests, headers: map[string]string{"Retry-After": "0"}},
+				success,
+			},
+			expectedCalls: 2,
+		},
+		"does not retry documented client errors": {
+			policy: RetryPolicy{MaxRetries: 3, Backoff: ConstantBackoff(0)},
+			responses: []response{
+				{
+					statusCode: http.StatusBadRequest,
+					body:       []byte(`{"error": {"code": "BadLanguage", "message": "language is invalid"}}`),
+				},
+				success,
+			},
+			expectedCalls: 1,
+			expectedError: ErrBadLanguage,
+		},
+		"does not retry quota exceeded": {
+			policy: RetryPolicy{MaxRetries: 3, Backoff: ConstantBackoff(0)},
+			responses: []response{
+				{
+					statusCode: http.StatusPaymentRequired,
+					body:       []byte(`{"error": {"code": "QuotaExceeded", "message": "Quota Exceeded"}}`),
+				},
+				success,
+			},
+			expectedCalls: 1,
+			expectedError: ErrQuotaExceeded,
+		},
+		"gives up after max retries": {
+			policy: RetryPolicy{MaxRetries: 2, Backoff: ConstantBackoff(0)},
+			responses: []response{
+				{
+					statusCode: http.StatusInternalServerError,
+					body:       []byte(`{"error": {"code": "InternalServerError", "message": "internal error"}}`),
+				},
+				{
+					statusCode: http.StatusInternalServerError,
+					body:       []byte(`{"error": {"code": "InternalServerError", "message": "internal error"}}`),
+				},
+				{
+					statusCode: http.StatusInternalServerError,
+					body:       []byte(`{"error": {"code": "InternalServerError", "message": "internal error"}}`),
+				},
+				success,
+			},
+			expectedCalls: 3,
+			expectedError: ErrInternalServerError,
+		},
+		"does not wait for retry after beyond context deadline": {
+			policy:  RetryPolicy{MaxRetries: 3, Backoff: ConstantBackoff(0)},
+			timeout: time.Second,
+			responses: []response{
+				{
+					statusCode: http.StatusServiceUnavailable,
+					headers:    map[string]string{"Retry-After": "120"},
+					body:       []byte(`{"error": {"code": "InternalServerError", "message": "try later"}}`),
+				},
+				success,
+			},
+			expectedCalls: 1,
+			expectedError: ErrInternalServerError,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls int32
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				resp := tt.responses[atomic.AddInt32(&calls, 1)-1]
+				for k, v := range resp.headers {
+					rw.Header().Set(k, v)
+				}
+				rw.WriteHeader(resp.statusCode)
+				_, err := rw.Write(resp.body)
+				assert.NoError(t, err)
+			}))
+			defer ts.Close()
+
+			u, err := url.Parse(ts.URL)
+			assert.NoError(t, err)
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			w := NewClient("example-api-key", WithEndpoint(u), WithRetry(tt.policy))
+			_, err = w.AvailableLanguages(ctx)
+			if tt.expectedError != "" {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second)
+
+	tests := map[string]struct {
+		attempt  int
+		maxDelay time.Duration
+	}{
+		"first attempt is capped by base":   {attempt: 1, maxDelay: 100 * time.Millisecond},
+		"third attempt doubles twice":       {attempt: 3, maxDelay: 400 * time.Millisecond},
+		"large attempts are capped by max":  {attempt: 50, maxDelay: time.Second},
+		"attempts after max stay at max":    {attempt: 5, maxDelay: time.Second},
+		"second attempt doubles base delay": {attempt: 2, maxDelay: 200 * time.Millisecond},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				delay := backoff(tt.attempt)
+				assert.GreaterOrEqual(t, delay, time.Duration(0))
+				assert.LessOrEqual(t, delay, tt.maxDelay)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		value    string
+		expected time.Duration
+	}{
+		"missing header":    {value: "", expected: 0},
+		"delay in seconds":  {value: "3", expected: 3 * time.Second},
+		"http date":         {value: "Sat, 01 Apr 2023 12:00:30 GMT", expected: 30 * time.Second},
+		"http date in past": {value: "Sat, 01 Apr 2023 11:00:00 GMT", expected: 0},
+		"invalid value":     {value: "soon", expected: 0},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.value != "" {
+				header.Set("Retry-After", tt.value)
+			}
+			assert.Equal(t, tt.expected, parseRetryAfter(header, now))
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package simulator
+
+import (
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+const (
+	// _maxEditDistance is the largest edit distance between a typed word and a word in the list for the word to be
+	// considered a match.
+	_maxEditDistance = 2
+
+	// _candidatesPerWord is the number of closest matches considered for each of the first two typed words.
+	_candidatesPerWord = 5
+
+	// _candidatesPerPartialWord is the largest number of equally close matches considered for the last typed word,
+	// which may be incomplete, so that the focus and clipping can choose between them.
+	_candidatesPerPartialWord = 500
+
+	// _searchHalfSquares is the number of squares searched in each direction from the focus, or from the centre of the
+	// clip area, so that nearby addresses are suggested however many addresses elsewhere match equally well. The area
+	// searched is about 1.5km across.
+	_searchHalfSquares = 250
+
+	_defaultNResults = 3
+	_maxNResults     = 100
+)
+
+// candidate is a word from the list which is close to a typed word.
+type candidate struct {
+	word     string
+	distance int
+}
+
+// suggestion is a possible 3 word address for an autosuggest input.
+type suggestion struct {
+	location what3words.LocationResponse
+	distance int
+	focusKm  float64
+}
+
+// clip restricts autosuggest results to an area.
+type clip func(c what3words.Coordinates, country string) bool
+
+// candidates returns the closest words in the list to a typed word. If partial is true the typed word may be
+// incomplete, so it is compared with the start of each word, and every word as close as the closest is returned.
+func (l *labels) candidates(typed string, partial bool) []candidate {
+	var candidates []candidate
+	for _, word := range l.words {
+		if d := wordDistance(typed, word, partial); d <= _maxEditDistance {
+			candidates = append(candidates, candidate{word: word, distance: d})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+	limit := _candidatesPerWord
+	if partial {
+		limit = _candidatesPerPartialWord
+		for i, c := range candidates {
+			if c.distance > candidates[0].distance {
+				candidates = candidates[:i]
+				break
+			}
+		}
+	}
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	return candidates
+}
+
+// wordDistance returns the edit distance between a typed word and a word in the list. If partial is true the typed
+// word may be incomplete, so it is compared with the start of the word.
+func wordDistance(typed, word string, partial bool) int {
+	if partial && len(word) > len(typed) {
+		word = word[:len(typed)]
+	}
+	return editDistance(typed, word)
+}
+
+// labelDistance returns the total edit distance between the typed words and the words of a label, and false if any
+// word is further than _maxEditDistance from the typed word. The last typed word may be incomplete.
+func labelDistance(typed []string, words [3]string) (int, bool) {
+	total := 0
+	for i, word := range words {
+		d := wordDistance(typed[i], word, i == len(words)-1)
+		if d > _maxEditDistance {
+			return 0, false
+		}
+		total += d
+	}
+	return total, true
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// Words are short, so the rows are usually kept on the stack, as editDistance is called for every square searched.
+	var prevBuf, currBuf [32]int
+	prev, curr := prevBuf[:0], currBuf[:0]
+	if len(rb) < len(prevBuf) {
+		prev, curr = prevBuf[:len(rb)+1], currBuf[:len(rb)+1]
+	} else {
+		prev, curr = make([]int, len(rb)+1), make([]int, len(rb)+1)
+	}
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = prev[j-1] + cost
+			if prev[j]+1 < curr[j] {
+				curr[j] = prev[j] + 1
+			}
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// suggest returns the 3 word addresses closest to the input which pass every clip, ranked by total edit distance
+// and then by distance from the focus. Suggestions are only made once the first two words and the start of the
+// third word have been typed. As neighbouring squares have unrelated labels, the closest labels in the word list are
+// scattered across the world, so the squares near the focus, or in the clip area bounded by bounds, are also searched.
+func (s *Simulator) suggest(input string, focus *what3words.Coordinates, clips []clip, bounds *what3words.BoundingBox) []suggestion {
+	parts := strings.Split(strings.ToLower(strings.TrimLeft(input, "/")), ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil
+	}
+
+	var suggestions []suggestion
+	seen := make(map[string]bool)
+	add := func(location what3words.LocationResponse, distance int) {
+		if seen[location.Words] || !allowed(clips, location) {
+			return
+		}
+		seen[location.Words] = true
+
+		sg := suggestion{location: location, distance: distance}
+		if focus != nil {
+			sg.focusKm = distanceKm(*focus, location.Coordinates)
+		}
+		suggestions = append(suggestions, sg)
+	}
+
+	first := s.labels.candidates(parts[0], false)
+	second := s.labels.candidates(parts[1], false)
+	third := s.labels.candidates(parts[2], true)
+	for _, a := range first {
+		for _, b := range second {
+			for _, c := range third {
+				if location, ok := s.locate(a.word + "." + b.word + "." + c.word); ok {
+					add(location, a.distance+b.distance+c.distance)
+				}
+			}
+		}
+	}
+
+	if centre, ok := searchCentre(focus, bounds); ok {
+		s.grid.near(centre, _searchHalfSquares, bounds, func(c cell) {
+			if distance, ok := labelDistance(parts, s.labels.labelWords(uint64(s.grid.index(c)))); ok {
+				add(s.location(c), distance)
+			}
+		})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].distance != suggestions[j].distance {
+			return suggestions[i].distance < suggestions[j].distance
+		}
+		if suggestions[i].focusKm != suggestions[j].focusKm {
+			return suggestions[i].focusKm < suggestions[j].focusKm
+		}
+		return suggestions[i].location.Words < suggestions[j].location.Words
+	})
+
+	return suggestions
+}
+
+// searchCentre returns the centre of the area searched for suggestions: the focus if it is inside the bounds of the
+// clip area, or otherwise the centre of the bounds. ok is false if there is neither a focus nor a clip area.
+func searchCentre(focus *what3words.Coordinates, bounds *what3words.BoundingBox) (centre what3words.Coordinates, ok bool) {
+	switch {
+	case focus != nil && (bounds == nil || bounds.Contains(*focus)):
+		return *focus, true
+	case bounds != nil && bounds.SouthLat <= bounds.NorthLat && bounds.WestLng <= bounds.EastLng:
+		return what3words.Coordinates{
+			Lat: (bounds.SouthLat + bounds.NorthLat) / 2,
+			Lng: (bounds.WestLng + bounds.EastLng) / 2,
+		}, true
+	default:
+		return what3words.Coordinates{}, false
+	}
+}
+
+// allowed reports whether the location passes every clip.
+func allowed(clips []clip, location what3words.LocationResponse) bool {
+	for _, c := range clips {
+		if !c(location.Coordinates, location.Country) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Simulator) autoSuggest(rw http.ResponseWriter, query url.Values, withCoordinates bool) {
+	input := query.Get("input")
+	if input == "" {
+		writeError(rw, http.StatusBadRequest, what3words.ErrMissingInput, "input must be specified")
+		return
+	}
+
+	nResults := _defaultNResults
+	if value := query.Get("n-results"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > _maxNResults {
+			writeError(rw, http.StatusBadRequest, what3words.ErrBadNResults, "n-results must be between 1 and 100")
+			return
+		}
+		nResults = n
+	}
+
+	var focus *what3words.Coordinates
+	if value := query.Get("focus"); value != "" {
+		values, err := parseFloats(value, 2)
+		if err != nil || !validCoordinates(values[0], values[1]) {
+			writeError(rw, http.StatusBadRequest, what3words.ErrBadFocus, "focus must be valid lat,lng coordinates")
+			return
+		}
+		focus = &what3words.Coordinates{Lat: values[0], Lng: values[1]}
+	}
+
+	clips, bounds, apiErr := parseClips(query)
+	if apiErr != nil {
+		writeError(rw, http.StatusBadRequest, apiErr.Code, apiErr.Message)
+		return
+	}
+
+	suggestions := s.suggest(input, focus, clips, bounds)
+	if len(suggestions) > nResults {
+		suggestions = suggestions[:nResults]
+	}
+
+	resp := what3words.AutoSuggestWithCoordinatesResponse{
+		Suggestions: make([]what3words.SuggestionWithCoordinates, 0, len(suggestions)),
+	}
+	for i, sg := range suggestions {
+		suggestion := what3words.SuggestionWithCoordinates{
+			Suggestion: what3words.Suggestion{
+				Country:  sg.location.Country,
+				Words:    sg.location.Words,
+				Rank:     i + 1,
+				Language: sg.location.Language,
+			},
+			Coordinates: sg.location.Coordinates,
+			Square:      sg.location.Square,
+			Map:         sg.location.Map,
+		}
+		if focus != nil {
+			suggestion.DistanceToFocusKm = int(math.Round(sg.focusKm))
+		}
+		resp.Suggestions = append(resp.Suggestions, suggestion)
+	}
+
+	if withCoordinates {
+		writeJSON(rw, resp)
+		return
+	}
+
+	plain := what3words.AutoSuggestResponse{Suggestions: make([]what3words.Suggestion, 0, len(resp.Suggestions))}
+	for _, suggestion := range resp.Suggestions {
+		plain.Suggestions = append(plain.Suggestions, suggestion.Suggestion)
+	}
+	writeJSON(rw, plain)
+}
+
+// parseClips parses the clip-to-country, clip-to-bounding-box, clip-to-circle and clip-to-polygon parameters. bounds
+// is the intersection of the bounding boxes of the clip areas, or nil if there are none. Areas crossing the
+// antimeridian do not bound the search.
+func parseClips(query url.Values) (clips []clip, bounds *what3words.BoundingBox, apiErr *what3words.APIError) {
+	within := func(box what3words.BoundingBox) {
+		switch {
+		case box.WestLng > box.EastLng:
+			return
+		case bounds == nil:
+			bounds = &box
+			return
+		}
+
+		bounds.SouthLat = math.Max(bounds.SouthLat, box.SouthLat)
+		bounds.WestLng = math.Max(bounds.WestLng, box.WestLng)
+		bounds.NorthLat = math.Min(bounds.NorthLat, box.NorthLat)
+		bounds.EastLng = math.Min(bounds.EastLng, box.EastLng)
+	}
+
+	if value := query.Get("clip-to-country"); value != "" {
+		countries := strings.Split(value, ",")
+		clips = append(clips, func(_ what3words.Coordinates, country string) bool {
+			for _, c := range countries {
+				if strings.EqualFold(c, country) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+
+	if value := query.Get("clip-to-bounding-box"); value != "" {
+		box, err := parseBoundingBox(value)
+		if err != nil {
+			return nil, nil, &what3words.APIError{Code: what3words.ErrBadClipToBoundingBox, Message: err.Error()}
+		}
+		within(box)
+		clips = append(clips, func(c what3words.Coordinates, _ string) bool {
+			return box.Contains(c)
+		})
+	}
+
+	if value := query.Get("clip-to-circle"); value != "" {
+		values, err := parseFloats(value, 3)
+		if err != nil || !validCoordinates(values[0], values[1]) || values[2] <= 0 {
+			return nil, nil, &what3words.APIError{Code: what3words.ErrBadClipToCircle, Message: "clip-to-circle must be lat,lng,kilometres"}
+		}
+		centre := what3words.Coordinates{Lat: values[0], Lng: values[1]}
+		within(*what3words.NewBoundingBoxFromCentre(centre, values[2]*1000))
+		clips = append(clips, func(c what3words.Coordinates, _ string) bool {
+			return distanceKm(centre, c) <= values[2]
+		})
+	}
+
+	if value := query.Get("clip-to-polygon"); value != "" {
+		values, err := parseFloats(value, strings.Count(value, ",")+1)
+		if err != nil || len(values)%2 != 0 || len(values) < 8 {
+			return nil, nil, &what3words.APIError{
+				Code:    what3words.ErrBadClipToPolygon,
+				Message: "clip-to-polygon must be at least 4 lat,lng pairs, with the first and last the same",
+			}
+		}
+
+		polygon := make(what3words.PolygonCoordinates, 0, len(values)/2)
+		box := what3words.BoundingBox{SouthLat: 90, WestLng: 180, NorthLat: -90, EastLng: -180}
+		for i := 0; i < len(values); i += 2 {
+			polygon = append(polygon, what3words.Coordinates{Lat: values[i], Lng: values[i+1]})
+			box.SouthLat, box.NorthLat = math.Min(box.SouthLat, values[i]), math.Max(box.NorthLat, values[i])
+			box.WestLng, box.EastLng = math.Min(box.WestLng, values[i+1]), math.Max(box.EastLng, values[i+1])
+		}
+		within(box)
+		clips = append(clips, func(c what3words.Coordinates, _ string) bool {
+			return polygon.Contains(c)
+		})
+	}
+
+	return clips, bounds, nil
+}
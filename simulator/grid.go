@@ -0,0 +1,197 @@
+package simulator
+
+import (
+	"math"
+	"sort"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+// The world is divided into rows of 1/36000 of a degree of latitude, about 3.09m. Rows are grouped into bands of
+// 0.1 degrees, and each band is divided into as many equal columns as keep its squares no wider than they are tall,
+// so squares are roughly 3m x 3m everywhere rather than narrowing towards the poles. Only within a degree of the poles,
+// where the circumference changes fastest, do squares become noticeably narrower than 3m.
+const (
+	_rowsPerDegree = 36000
+	_bandRows      = 3600
+	_rows          = 180 * _rowsPerDegree
+	_bands         = _rows / _bandRows
+)
+
+// cell identifies a grid square by its row and column. Columns are counted from 180 degrees west within the row's band.
+type cell struct {
+	row int64
+	col int64
+}
+
+// grid holds the number of columns in each band and the index of the first square of each band.
+type grid struct {
+	cols  [_bands]int64
+	start [_bands + 1]int64
+}
+
+func newGrid() *grid {
+	g := &grid{}
+	for b := 0; b < _bands; b++ {
+		south, north := bandLat(b), bandLat(b+1)
+		edge := math.Min(math.Abs(south), math.Abs(north))
+		g.cols[b] = int64(math.Ceil(360 * _rowsPerDegree * math.Cos(edge*math.Pi/180)))
+		g.start[b+1] = g.start[b] + g.cols[b]*_bandRows
+	}
+	return g
+}
+
+// size returns the number of squares in the grid.
+func (g *grid) size() int64 {
+	return g.start[_bands]
+}
+
+// bandLat returns the latitude of the southern edge of band b.
+func bandLat(b int) float64 {
+	return -90 + float64(b*_bandRows)/_rowsPerDegree
+}
+
+// rowLat returns the latitude of the southern edge of a row.
+func rowLat(row int64) float64 {
+	return -90 + float64(row)/_rowsPerDegree
+}
+
+// colLng returns the longitude of the western edge of a column in band b.
+func (g *grid) colLng(b int, col int64) float64 {
+	return -180 + float64(col)*360/float64(g.cols[b])
+}
+
+// cellAt returns the square containing the coordinates. Longitudes outside -180 to 180 are wrapped.
+func (g *grid) cellAt(c what3words.Coordinates) cell {
+	row := int64(math.Floor((c.Lat + 90) * _rowsPerDegree))
+	if row >= _rows {
+		row = _rows - 1
+	}
+	if row < 0 {
+		row = 0
+	}
+
+	lng := math.Mod(c.Lng+180, 360)
+	if lng < 0 {
+		lng += 360
+	}
+
+	cols := g.cols[row/_bandRows]
+	col := int64(math.Floor(lng * float64(cols) / 360))
+	if col >= cols {
+		col = cols - 1
+	}
+
+	return cell{row: row, col: col}
+}
+
+// index returns the position of the square in the grid, from 0 to size()-1.
+func (g *grid) index(c cell) int64 {
+	b := c.row / _bandRows
+	return g.start[b] + (c.row-b*_bandRows)*g.cols[b] + c.col
+}
+
+// cell returns the square at a position in the grid. ok is false if the index is outside the grid.
+func (g *grid) cell(index int64) (c cell, ok bool) {
+	if index < 0 || index >= g.size() {
+		return cell{}, false
+	}
+
+	b := sort.Search(_bands, func(b int) bool { return g.start[b+1] > index })
+
+	offset := index - g.start[b]
+	return cell{row: int64(b*_bandRows) + offset/g.cols[b], col: offset % g.cols[b]}, true
+}
+
+// square returns the bounds of a grid square.
+func (g *grid) square(c cell) what3words.Square {
+	b := int(c.row / _bandRows)
+	return what3words.Square{
+		Southwest: what3words.Coordinates{Lat: rowLat(c.row), Lng: g.colLng(b, c.col)},
+		Northeast: what3words.Coordinates{Lat: rowLat(c.row + 1), Lng: g.colLng(b, c.col+1)},
+	}
+}
+
+// near calls fn for every square within half rows and columns of the square containing the centre, skipping squares
+// outside the bounds if any. Columns wrap around the antimeridian.
+func (g *grid) near(centre what3words.Coordinates, half int64, bounds *what3words.BoundingBox, fn func(cell)) {
+	c := g.cellAt(centre)
+	first, last := c.row-half, c.row+half
+	if bounds != nil {
+		if south := g.cellAt(what3words.Coordinates{Lat: bounds.SouthLat}).row; south > first {
+			first = south
+		}
+		if north := g.cellAt(what3words.Coordinates{Lat: bounds.NorthLat}).row; north < last {
+			last = north
+		}
+	}
+	if first < 0 {
+		first = 0
+	}
+	if last >= _rows {
+		last = _rows - 1
+	}
+
+	for row := first; row <= last; row++ {
+		lat := rowLat(row) + 0.5/_rowsPerDegree
+		cols := g.cols[row/_bandRows]
+
+		mid := g.cellAt(what3words.Coordinates{Lat: lat, Lng: centre.Lng}).col
+		west, east := mid-half, mid+half
+		if bounds != nil {
+			if col := g.cellAt(what3words.Coordinates{Lat: lat, Lng: bounds.WestLng}).col; col > west {
+				west = col
+			}
+			if col := g.cellAt(what3words.Coordinates{Lat: lat, Lng: bounds.EastLng}).col; col < east && bounds.EastLng < 180 {
+				east = col
+			}
+		}
+		if east-west >= cols {
+			east = west + cols - 1
+		}
+
+		for col := west; col <= east; col++ {
+			fn(cell{row: row, col: (col%cols + cols) % cols})
+		}
+	}
+}
+
+// lines returns the edges of every grid square within the bounding box, clipped to the box. Horizontal lines are
+// returned first, from south to north, followed by vertical lines from west to east within each band.
+func (g *grid) lines(box what3words.BoundingBox) []what3words.GridLine {
+	var lines []what3words.GridLine
+
+	for row := int64(math.Ceil((box.SouthLat + 90) * _rowsPerDegree)); row <= _rows && rowLat(row) <= box.NorthLat; row++ {
+		lat := rowLat(row)
+		lines = append(lines, what3words.GridLine{
+			Start: what3words.Coordinates{Lat: lat, Lng: box.WestLng},
+			End:   what3words.Coordinates{Lat: lat, Lng: box.EastLng},
+		})
+	}
+
+	first := g.cellAt(what3words.Coordinates{Lat: box.SouthLat, Lng: box.WestLng}).row / _bandRows
+	last := g.cellAt(what3words.Coordinates{Lat: box.NorthLat, Lng: box.WestLng}).row / _bandRows
+	for b := int(first); b <= int(last); b++ {
+		south := math.Max(box.SouthLat, bandLat(b))
+		north := math.Min(box.NorthLat, bandLat(b+1))
+		if south >= north {
+			continue
+		}
+
+		cols := float64(g.cols[b])
+		for col := int64(math.Ceil((box.WestLng + 180) * cols / 360)); g.colLng(b, col) <= box.EastLng; col++ {
+			lng := g.colLng(b, col)
+			lines = append(lines, what3words.GridLine{
+				Start: what3words.Coordinates{Lat: south, Lng: lng},
+				End:   what3words.Coordinates{Lat: north, Lng: lng},
+			})
+		}
+	}
+
+	return lines
+}
+
+// distanceKm returns the great circle distance between two points in kilometres.
+func distanceKm(a, b what3words.Coordinates) float64 {
+	return a.DistanceTo(b) / 1000
+}
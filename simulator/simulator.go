@@ -0,0 +1,262 @@
+// Package simulator provides a deterministic stand-in for the what3words API, for load tests and CI.
+//
+// The simulator divides the world into squares of roughly 3m x 3m and gives every square a stable three word label
+// made from a bundled list of invented words, so it can convert any coordinates to a 3 word address and back again
+// without fixtures. Labels are reversible and never change between runs or machines. All squares are reported in
+// country ZZ, as the simulator has no knowledge of real places.
+//
+// A Simulator implements the what3words.What3Words interface and never contacts the real service:
+//
+//	sim := simulator.New()
+//	resp, err := sim.ConvertTo3wa(ctx, &what3words.Coordinates{Lat: 51.520847, Lng: -0.195521})
+//
+// It is also an http.Handler serving the v3 API, so it can stand in for the service for code in other languages:
+//
+//	log.Fatal(http.ListenAndServe(":8080", simulator.New()))
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+const (
+	// _endpoint is the endpoint used by the simulator's own client. Requests to it never leave the process.
+	_endpoint = "http://simulator.invalid/v3"
+
+	// _country is the country code reported for every square.
+	_country = "ZZ"
+
+	// _maxGridDiagonalKm is the largest bounding box diagonal accepted by grid-section.
+	_maxGridDiagonalKm = 4
+)
+
+// Simulator is a deterministic what3words API simulator. It implements the what3words.What3Words interface and
+// http.Handler. A Simulator is safe for concurrent use.
+type Simulator struct {
+	what3words.What3Words
+
+	grid   *grid
+	labels *labels
+}
+
+// New creates a Simulator. Client options such as what3words.WithCache or what3words.WithRateLimit can be passed
+// to simulate client behaviour; options which change the endpoint or HTTP client are overridden.
+func New(opts ...what3words.Option) *Simulator {
+	s := &Simulator{
+		grid:   newGrid(),
+		labels: newLabels(),
+	}
+
+	endpoint, _ := url.Parse(_endpoint)
+	opts = append(opts,
+		what3words.WithEndpoint(endpoint),
+		what3words.WithHTTPClient(&http.Client{Transport: transport{handler: s}}),
+	)
+	s.What3Words = what3words.NewClient("simulator", opts...)
+
+	return s
+}
+
+// transport is an http.RoundTripper which serves requests in process with a handler.
+type transport struct {
+	handler http.Handler
+}
+
+// RoundTrip serves the request with the handler.
+func (t transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if err := r.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, r)
+
+	resp := rec.Result()
+	resp.Request = r
+	return resp, nil
+}
+
+// ServeHTTP serves the what3words v3 API. Any API key is accepted and only English is supported.
+func (s *Simulator) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if language := query.Get("language"); language != "" && language != "en" {
+		writeError(rw, http.StatusBadRequest, what3words.ErrBadLanguage, "language must be one of the available languages: en")
+		return
+	}
+
+	switch path.Base(r.URL.Path) {
+	case "convert-to-3wa":
+		s.convertTo3wa(rw, query)
+	case "convert-to-coordinates":
+		s.convertToCoordinates(rw, query)
+	case "autosuggest":
+		s.autoSuggest(rw, query, false)
+	case "autosuggest-with-coordinates":
+		s.autoSuggest(rw, query, true)
+	case "autosuggest-selection":
+		rw.WriteHeader(http.StatusOK)
+	case "grid-section":
+		s.gridSection(rw, query)
+	case "available-languages":
+		writeJSON(rw, what3words.AvailableLanguages{
+			Languages: []what3words.Language{{Code: "en", Name: "English", NativeName: "English"}},
+		})
+	default:
+		writeError(rw, http.StatusNotFound, "NotFound", fmt.Sprintf("endpoint %s not found", path.Base(r.URL.Path)))
+	}
+}
+
+// location returns the location of a grid square.
+func (s *Simulator) location(c cell) what3words.LocationResponse {
+	words := s.labels.label(uint64(s.grid.index(c)))
+	square := s.grid.square(c)
+
+	return what3words.LocationResponse{
+		Coordinates: square.Centre(),
+		Country:     _country,
+		Language:    "en",
+		Map:         "https://w3w.co/" + words,
+		Square:      square,
+		Words:       words,
+	}
+}
+
+// locate returns the location of a label, or false if the label does not identify a grid square.
+func (s *Simulator) locate(words string) (what3words.LocationResponse, bool) {
+	index, ok := s.labels.index(words)
+	if !ok || index >= uint64(s.grid.size()) {
+		return what3words.LocationResponse{}, false
+	}
+
+	c, _ := s.grid.cell(int64(index))
+	return s.location(c), true
+}
+
+func (s *Simulator) convertTo3wa(rw http.ResponseWriter, query url.Values) {
+	if query.Get("coordinates") == "" {
+		writeError(rw, http.StatusBadRequest, what3words.ErrMissingCoordinates, "coordinates must be specified")
+		return
+	}
+
+	values, err := parseFloats(query.Get("coordinates"), 2)
+	if err != nil || !validCoordinates(values[0], values[1]) {
+		writeError(rw, http.StatusBadRequest, what3words.ErrBadCoordinates,
+			"coordinates must be two comma separated lat,lng coordinates, with lat between -90 and 90 and lng between -180 and 180")
+		return
+	}
+
+	writeJSON(rw, s.location(s.grid.cellAt(what3words.Coordinates{Lat: values[0], Lng: values[1]})))
+}
+
+func (s *Simulator) convertToCoordinates(rw http.ResponseWriter, query url.Values) {
+	words := strings.ToLower(strings.TrimLeft(query.Get("words"), "/"))
+	if words == "" {
+		writeError(rw, http.StatusBadRequest, what3words.ErrMissingWords, "words must be specified")
+		return
+	}
+
+	location, ok := s.locate(words)
+	if !ok {
+		writeError(rw, http.StatusBadRequest, what3words.ErrBadWords,
+			"words must be a valid 3 word address, such as filled.count.soap or ///filled.count.soap")
+		return
+	}
+
+	writeJSON(rw, location)
+}
+
+func (s *Simulator) gridSection(rw http.ResponseWriter, query url.Values) {
+	if query.Get("bounding-box") == "" {
+		writeError(rw, http.StatusBadRequest, what3words.ErrMissingBoundingBox, "bounding-box must be specified")
+		return
+	}
+
+	box, err := parseBoundingBox(query.Get("bounding-box"))
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, what3words.ErrBadBoundingBox, err.Error())
+		return
+	}
+
+	sw := what3words.Coordinates{Lat: box.SouthLat, Lng: box.WestLng}
+	ne := what3words.Coordinates{Lat: box.NorthLat, Lng: box.EastLng}
+	if distanceKm(sw, ne) > _maxGridDiagonalKm {
+		writeError(rw, http.StatusBadRequest, what3words.ErrBadBoundingBoxTooBig,
+			fmt.Sprintf("the diagonal of bounding-box may not be greater than %dkm", _maxGridDiagonalKm))
+		return
+	}
+
+	writeJSON(rw, what3words.GridSection{Lines: s.grid.lines(box)})
+}
+
+// parseFloats parses n comma separated numbers.
+func parseFloats(value string, n int) ([]float64, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != n {
+		return nil, fmt.Errorf("expected %d comma separated numbers, got %d", n, len(parts))
+	}
+
+	values := make([]float64, 0, n)
+	for _, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("invalid number %q", part)
+		}
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// parseBoundingBox parses a bounding box in the "south,west,north,east" format. Boxes crossing the antimeridian
+// are not supported.
+func parseBoundingBox(value string) (what3words.BoundingBox, error) {
+	values, err := parseFloats(value, 4)
+	if err != nil {
+		return what3words.BoundingBox{}, fmt.Errorf("bounding box must be south,west,north,east: %w", err)
+	}
+
+	box := what3words.BoundingBox{SouthLat: values[0], WestLng: values[1], NorthLat: values[2], EastLng: values[3]}
+	switch {
+	case !validCoordinates(box.SouthLat, box.WestLng) || !validCoordinates(box.NorthLat, box.EastLng):
+		return what3words.BoundingBox{}, fmt.Errorf("bounding box coordinates are out of range")
+	case box.SouthLat > box.NorthLat:
+		return what3words.BoundingBox{}, fmt.Errorf("bounding box south latitude must be less than its north latitude")
+	case box.WestLng > box.EastLng:
+		return what3words.BoundingBox{}, fmt.Errorf("bounding box west longitude must be less than its east longitude")
+	}
+
+	return box, nil
+}
+
+// validCoordinates reports whether the latitude and longitude are within range.
+func validCoordinates(lat, lng float64) bool {
+	return lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}
+
+func writeJSON(rw http.ResponseWriter, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(v)
+}
+
+// writeError writes the what3words error payload for the code and message.
+func writeError(rw http.ResponseWriter, statusCode int, code what3words.ErrorCode, message string) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(statusCode)
+	_ = json.NewEncoder(rw).Encode(map[string]interface{}{
+		"error": map[string]string{
+			"code":    string(code),
+			"message": message,
+		},
+	})
+}
@@ -0,0 +1,255 @@
+package simulator
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+func TestSimulator_RoundTrip(t *testing.T) {
+	sim := New()
+	ctx := context.Background()
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 500; i++ {
+		// Coordinates are sent with 6 decimal places, so round them to compare with the square.
+		coordinates := &what3words.Coordinates{
+			Lat: math.Round((random.Float64()*180-90)*1e6) / 1e6,
+			Lng: math.Round((random.Float64()*360-180)*1e6) / 1e6,
+		}
+
+		location, err := sim.ConvertTo3wa(ctx, coordinates)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.True(t, what3words.IsPossible3wa(location.Words))
+		assert.LessOrEqual(t, location.Square.Southwest.Lat, coordinates.Lat)
+		assert.GreaterOrEqual(t, location.Square.Northeast.Lat, coordinates.Lat)
+		assert.LessOrEqual(t, location.Square.Southwest.Lng, coordinates.Lng)
+		assert.GreaterOrEqual(t, location.Square.Northeast.Lng, coordinates.Lng)
+
+		back, err := sim.ConvertToCoordinates(ctx, location.Words)
+		assert.NoError(t, err)
+		assert.Equal(t, location, back)
+	}
+}
+
+func TestSimulator_Deterministic(t *testing.T) {
+	location, err := New().ConvertTo3wa(context.Background(), &what3words.Coordinates{Lat: 51.520847, Lng: -0.195521})
+	assert.NoError(t, err)
+	assert.Equal(t, "rufin.buset.bonas", location.Words)
+	assert.Equal(t, "ZZ", location.Country)
+
+	neighbour, err := New().ConvertTo3wa(context.Background(), &what3words.Coordinates{Lat: 51.520847, Lng: -0.19548})
+	assert.NoError(t, err)
+	assert.Equal(t, location.Square.Northeast.Lng, neighbour.Square.Southwest.Lng)
+	assert.NotEqual(t, location.Words, neighbour.Words)
+}
+
+func TestSimulator_SquareSize(t *testing.T) {
+	g := newGrid()
+
+	for _, lat := range []float64{-85.05, -60, -0.01, 0, 30, 51.5, 75, 85.05} {
+		square := g.square(g.cellAt(what3words.Coordinates{Lat: lat, Lng: 10}))
+		height := distanceKm(square.Southwest, what3words.Coordinates{Lat: square.Northeast.Lat, Lng: square.Southwest.Lng})
+		width := distanceKm(what3words.Coordinates{Lat: lat, Lng: square.Southwest.Lng}, what3words.Coordinates{Lat: lat, Lng: square.Northeast.Lng})
+
+		assert.InDelta(t, 0.00309, height, 0.00001, "height at %v", lat)
+		assert.InDelta(t, 0.0028, width, 0.0003, "width at %v", lat)
+	}
+}
+
+func TestSimulator_GridSection(t *testing.T) {
+	sim := New()
+	ctx := context.Background()
+
+	box := &what3words.BoundingBox{SouthLat: 51.5208, WestLng: -0.1956, NorthLat: 51.5209, EastLng: -0.19545}
+	grid, err := sim.GridSection(ctx, box)
+	assert.NoError(t, err)
+
+	lats := map[float64]bool{}
+	lngs := map[float64]bool{}
+	for _, line := range grid.Lines {
+		if line.Start.Lat == line.End.Lat {
+			lats[line.Start.Lat] = true
+		} else {
+			lngs[line.Start.Lng] = true
+		}
+	}
+
+	location, err := sim.ConvertTo3wa(ctx, &what3words.Coordinates{Lat: 51.52085, Lng: -0.1955})
+	assert.NoError(t, err)
+	assert.True(t, lats[location.Square.Southwest.Lat])
+	assert.True(t, lats[location.Square.Northeast.Lat])
+	assert.True(t, lngs[location.Square.Southwest.Lng])
+	assert.True(t, lngs[location.Square.Northeast.Lng])
+
+	_, err = sim.GridSection(ctx, &what3words.BoundingBox{SouthLat: 51, WestLng: -1, NorthLat: 52, EastLng: 0})
+	assert.ErrorIs(t, err, what3words.ErrBadBoundingBoxTooBig)
+
+	// Larger boxes are split into tiles the simulator accepts, and merged into the lines of the whole box.
+	large := what3words.BoundingBox{SouthLat: 51.5, WestLng: -0.2, NorthLat: 51.53, EastLng: -0.15}
+	grid, err = sim.GridSectionLarge(ctx, &large, what3words.BatchOptions{})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, sim.grid.lines(large), grid.Lines)
+}
+
+func TestSimulator_AutoSuggest(t *testing.T) {
+	sim := New()
+	ctx := context.Background()
+
+	target := what3words.Coordinates{Lat: 51.520847, Lng: -0.195521}
+	location, err := sim.ConvertTo3wa(ctx, &target)
+	assert.NoError(t, err)
+
+	// Only the start of the last word is typed, so thousands of addresses across the world match equally well.
+	words := strings.Split(location.Words, ".")
+	short := words[0] + "." + words[1] + "." + words[2][:1]
+	nearby := target.Destination(0, 400)
+
+	tests := map[string]struct {
+		input    *what3words.AutoSuggestInput
+		expected string
+		excluded string
+		empty    bool
+	}{
+		"exact address": {
+			input:    &what3words.AutoSuggestInput{Words: "rufin.buset.bonas"},
+			expected: "rufin.buset.bonas",
+		},
+		"typo in first word": {
+			input:    &what3words.AutoSuggestInput{Words: "rufiin.buset.bonas"},
+			expected: "rufin.buset.bonas",
+		},
+		"partial last word with focus": {
+			input:    &what3words.AutoSuggestInput{Words: "rufin.buset.bon", Focus: &target},
+			expected: "rufin.buset.bonas",
+		},
+		"clipped to circle": {
+			input:    &what3words.AutoSuggestInput{Words: "rufin.buset.bon", ClipToCircle: &what3words.CoordinateRadius{Coordinates: target, Radius: 1}},
+			expected: "rufin.buset.bonas",
+		},
+		"short last word with nearby focus": {
+			input:    &what3words.AutoSuggestInput{Words: short, Focus: &nearby},
+			expected: "rufin.buset.bonas",
+		},
+		"short last word clipped to circle": {
+			input:    &what3words.AutoSuggestInput{Words: short, ClipToCircle: &what3words.CoordinateRadius{Coordinates: nearby, Radius: 1}},
+			expected: "rufin.buset.bonas",
+		},
+		"short last word clipped to bounding box": {
+			input:    &what3words.AutoSuggestInput{Words: short, ClipToBoundingBox: what3words.NewBoundingBox(51.518, -0.2, 51.526, -0.19)},
+			expected: "rufin.buset.bonas",
+		},
+		"short last word clipped to polygon": {
+			input: &what3words.AutoSuggestInput{Words: short, ClipToPolygon: what3words.PolygonCoordinates{
+				{Lat: 51.518, Lng: -0.2}, {Lat: 51.518, Lng: -0.19}, {Lat: 51.526, Lng: -0.19}, {Lat: 51.518, Lng: -0.2},
+			}},
+			expected: "rufin.buset.bonas",
+		},
+		"clipped to bounding box": {
+			// Addresses in the box within a few typos of the input may still be suggested.
+			input:    &what3words.AutoSuggestInput{Words: "rufin.buset.bonas", ClipToBoundingBox: what3words.NewBoundingBox(-10, -10, 10, 10)},
+			excluded: "rufin.buset.bonas",
+		},
+		"clipped to country": {
+			input: &what3words.AutoSuggestInput{Words: "rufin.buset.bonas", ClipToCountry: []string{"GB"}},
+			empty: true,
+		},
+		"unknown words": {
+			input: &what3words.AutoSuggestInput{Words: "xxxxx.yyyyy.zzzzz"},
+			empty: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := sim.AutoSuggestWithCoordinates(ctx, tt.input)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			if tt.empty {
+				assert.Empty(t, resp.Suggestions)
+				return
+			}
+			if tt.excluded != "" {
+				for _, suggestion := range resp.Suggestions {
+					assert.NotEqual(t, tt.excluded, suggestion.Words)
+					if tt.input.ClipToBoundingBox != nil {
+						assert.True(t, tt.input.ClipToBoundingBox.Contains(suggestion.Coordinates))
+					}
+				}
+				return
+			}
+			if assert.NotEmpty(t, resp.Suggestions) {
+				assert.Equal(t, tt.expected, resp.Suggestions[0].Words)
+				assert.Equal(t, 1, resp.Suggestions[0].Rank)
+				assert.Equal(t, location.Square, resp.Suggestions[0].Square)
+			}
+		})
+	}
+}
+
+func TestSimulator_Errors(t *testing.T) {
+	sim := New()
+	ctx := context.Background()
+
+	_, err := sim.ConvertToCoordinates(ctx, "filled.count.soap")
+	assert.ErrorIs(t, err, what3words.ErrBadWords)
+
+	// Invalid coordinates are rejected by the client before a request is made, and by the simulated API.
+	_, err = sim.ConvertTo3wa(ctx, &what3words.Coordinates{Lat: 91, Lng: 0})
+	var validationErr *what3words.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+
+	rec := httptest.NewRecorder()
+	sim.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v3/convert-to-3wa?coordinates=91,0", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), string(what3words.ErrBadCoordinates))
+
+	_, err = New(what3words.WithLanguage("fr")).AvailableLanguages(ctx)
+	assert.NoError(t, err)
+	_, err = New(what3words.WithLanguage("fr")).ConvertTo3wa(ctx, &what3words.Coordinates{Lat: 1, Lng: 1})
+	assert.ErrorIs(t, err, what3words.ErrBadLanguage)
+}
+
+func TestSimulator_ServeHTTP(t *testing.T) {
+	ts := httptest.NewServer(New())
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	client := what3words.NewClient("any-key", what3words.WithEndpoint(u), what3words.WithHTTPClient(http.DefaultClient))
+	location, err := client.ConvertToCoordinates(context.Background(), "rufin.buset.bonas")
+	assert.NoError(t, err)
+	assert.InDelta(t, 51.520847, location.Coordinates.Lat, 0.00003)
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := map[string]struct {
+		a, b     string
+		expected int
+	}{
+		"equal":        {a: "bonas", b: "bonas", expected: 0},
+		"substitution": {a: "bonas", b: "bonus", expected: 1},
+		"insertion":    {a: "bonas", b: "bonass", expected: 1},
+		"deletion":     {a: "bonas", b: "bnas", expected: 1},
+		"empty":        {a: "", b: "bonas", expected: 5},
+		"unrelated":    {a: "rufin", b: "bonas", expected: 5},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, editDistance(tt.a, tt.b))
+		})
+	}
+}
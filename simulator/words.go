@@ -0,0 +1,95 @@
+package simulator
+
+import (
+	_ "embed"
+	"math/big"
+	"math/bits"
+	"strings"
+)
+
+// words.txt contains 40,000 pronounceable invented words, so simulated 3 word addresses can never be mistaken for
+// real ones. Every label of three words identifies one of 40,000³ positions, which is more than the number of squares
+// in the grid.
+//
+//go:embed words.txt
+var wordList string
+
+// The index of a square is scrambled with an affine permutation of the label space before it is split into words,
+// so neighbouring squares have unrelated labels. _multiplier must be coprime with the size of the label space, and
+// both must be smaller than it.
+const (
+	_multiplier = 28_987_654_321_097
+	_offset     = 12_345_678_901_234
+)
+
+// labels converts between square indexes and three word labels.
+type labels struct {
+	words   []string
+	indexes map[string]uint64
+
+	// size is the number of labels, len(words)³.
+	size uint64
+
+	// inverse is the multiplicative inverse of _multiplier modulo size.
+	inverse uint64
+}
+
+func newLabels() *labels {
+	l := &labels{
+		words:   strings.Fields(wordList),
+		indexes: make(map[string]uint64),
+	}
+	for i, word := range l.words {
+		l.indexes[word] = uint64(i)
+	}
+
+	n := uint64(len(l.words))
+	l.size = n * n * n
+	l.inverse = new(big.Int).ModInverse(
+		new(big.Int).SetUint64(_multiplier),
+		new(big.Int).SetUint64(l.size),
+	).Uint64()
+
+	return l
+}
+
+// label returns the three word label of a square index.
+func (l *labels) label(index uint64) string {
+	words := l.labelWords(index)
+	return words[0] + "." + words[1] + "." + words[2]
+}
+
+// labelWords returns the three words of the label of a square index.
+func (l *labels) labelWords(index uint64) [3]string {
+	n := uint64(len(l.words))
+	p := (mulMod(index, _multiplier, l.size) + _offset) % l.size
+	return [3]string{l.words[p/(n*n)], l.words[p/n%n], l.words[p%n]}
+}
+
+// index returns the square index of a three word label. ok is false if any word is not in the word list.
+// The index may be beyond the end of the grid, as there are more labels than squares.
+func (l *labels) index(label string) (index uint64, ok bool) {
+	parts := strings.Split(label, ".")
+	if len(parts) != 3 {
+		return 0, false
+	}
+
+	n := uint64(len(l.words))
+	var p uint64
+	for _, part := range parts {
+		i, found := l.indexes[part]
+		if !found {
+			return 0, false
+		}
+		p = p*n + i
+	}
+
+	return mulMod((p+l.size-_offset)%l.size, l.inverse, l.size), true
+}
+
+// mulMod returns a*b mod m without overflowing.
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a%m, b%m)
+	_, rem := bits.Div64(hi, lo, m)
+	return rem
+}
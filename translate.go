@@ -0,0 +1,122 @@
+package what3words
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// _squareTolerance is the difference in degrees below which two grid square corners are considered the same,
+// allowing for the rounding of coordinates in API responses.
+const _squareTolerance = 1e-7
+
+// ErrSquareMismatch is returned when a translated 3 word address does not cover the same grid square as the original.
+var ErrSquareMismatch = errors.New("translated 3 word address is in a different square")
+
+// Translation is the 3 word address of a square in one language, returned by TranslateAll.
+type Translation struct {
+	// Language is the code of the language, or of the locale for languages written in several scripts, e.g. mn_la.
+	Language string
+
+	// Response is the location in the language, or nil if the translation failed.
+	Response *LocationResponse
+
+	// Err is the error translating into the language, if any.
+	Err error
+}
+
+// Translate returns the 3 word address of the same square as words in another language. The language is a language
+// code, or a locale code such as mn_la for languages written in several scripts. The square of words is found with
+// ConvertToCoordinates, and its centre is converted with ConvertTo3wa in the target language, so that rounding at
+// the edges of the square cannot select a neighbouring square. ErrSquareMismatch is returned if the translation
+// does not cover the same square. The CallOptions apply to both requests, apart from the language and locale.
+func (w *w3w) Translate(ctx context.Context, words, language string, opts ...CallOption) (*LocationResponse, error) {
+	source, err := w.ConvertToCoordinates(ctx, words, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("translating %q to %s: %w", words, language, err)
+	}
+
+	resp, err := w.translate(ctx, source, language, opts)
+	if err != nil {
+		return nil, fmt.Errorf("translating %q to %s: %w", words, language, err)
+	}
+
+	return resp, nil
+}
+
+// TranslateAll returns the 3 word address of the same square as words in every language from AvailableLanguages.
+// Languages written in several scripts are translated into each of their locales instead. Translations are made
+// concurrently in the same way as a batch, and are returned in the order of the available languages, each with its
+// own error. If the context is done before every translation completes, the translations made so far are returned
+// along with the context error.
+func (w *w3w) TranslateAll(ctx context.Context, words string, opts ...CallOption) ([]Translation, error) {
+	source, err := w.ConvertToCoordinates(ctx, words, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("translating %q: %w", words, err)
+	}
+
+	languages, err := w.AvailableLanguages(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("translating %q: %w", words, err)
+	}
+
+	var targets []string
+	for _, language := range languages {
+		if len(language.Locales) == 0 {
+			targets = append(targets, language.Code)
+			continue
+		}
+		for _, locale := range language.Locales {
+			targets = append(targets, locale.Code)
+		}
+	}
+
+	results, err := batch(ctx, len(targets), BatchOptions{}, func(ctx context.Context, i int) (*LocationResponse, error) {
+		return w.translate(ctx, source, targets[i], opts)
+	})
+
+	translations := make([]Translation, len(targets))
+	for i, result := range results {
+		translations[i] = Translation{Language: targets[i], Response: result.Response, Err: result.Err}
+	}
+	if err != nil {
+		return translations, fmt.Errorf("translating %q: %w", words, err)
+	}
+
+	return translations, nil
+}
+
+// translate converts the centre of the source square to a 3 word address in the language or locale,
+// checking that the result covers the same square.
+func (w *w3w) translate(ctx context.Context, source *LocationResponse, language string, opts []CallOption) (*LocationResponse, error) {
+	centre := source.Square.Centre()
+
+	resp, err := w.ConvertTo3wa(ctx, &centre, append(opts[:len(opts):len(opts)], translationOptions(language)...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if !sameSquare(source.Square, resp.Square) {
+		return nil, fmt.Errorf("%w: %s is not in the square of %s", ErrSquareMismatch, resp.Words, source.Words)
+	}
+
+	return resp, nil
+}
+
+// translationOptions returns the CallOptions selecting a language, or a locale and its language.
+func translationOptions(language string) []CallOption {
+	if code, _, ok := strings.Cut(language, "_"); ok {
+		return []CallOption{WithCallLanguage(code), WithCallLocale(language)}
+	}
+	return []CallOption{WithCallLanguage(language), WithCallLocale("")}
+}
+
+// sameSquare reports whether two squares have the same corners.
+func sameSquare(a, b Square) bool {
+	return math.Abs(a.Southwest.Lat-b.Southwest.Lat) < _squareTolerance &&
+		math.Abs(a.Southwest.Lng-b.Southwest.Lng) < _squareTolerance &&
+		math.Abs(a.Northeast.Lat-b.Northeast.Lat) < _squareTolerance &&
+		math.Abs(a.Northeast.Lng-b.Northeast.Lng) < _squareTolerance
+}
@@ -0,0 +1,183 @@
+package what3words
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTranslationServer serves the square of filled.count.soap in several languages. Words in the xx language
+// are in the neighbouring square, and the zz language is unavailable.
+func newTranslationServer(t *testing.T) (*httptest.Server, func() []url.Values) {
+	t.Helper()
+
+	square := Square{
+		Southwest: Coordinates{Lat: 51.520833, Lng: -0.195543},
+		Northeast: Coordinates{Lat: 51.52086, Lng: -0.195499},
+	}
+	neighbour := Square{
+		Southwest: Coordinates{Lat: 51.52086, Lng: -0.195543},
+		Northeast: Coordinates{Lat: 51.520887, Lng: -0.195499},
+	}
+	words := map[string]string{
+		"en":    "filled.count.soap",
+		"de":    "welche.tischtennis.bekannte",
+		"mn_la": "sankhuu.vidio.anchin",
+		"mn_cy": "санхүү.видео.анчин",
+		"xx":    "neighbouring.square.words",
+	}
+
+	var (
+		mu      sync.Mutex
+		queries []url.Values
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		mu.Lock()
+		queries = append(queries, query)
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/available-languages":
+			_, _ = rw.Write([]byte(`{"languages": [
+				{"code": "en", "name": "English", "nativeName": "English"},
+				{"code": "de", "name": "German", "nativeName": "Deutsch"},
+				{"code": "mn", "name": "Mongolian", "nativeName": "Монгол", "locales": [
+					{"code": "mn_la", "name": "Mongolian (Latin)", "nativeName": "Mongol (Latin)"},
+					{"code": "mn_cy", "name": "Mongolian (Cyrillic)", "nativeName": "Монгол (Кирилл)"}
+				]},
+				{"code": "xx", "name": "Neighbouring", "nativeName": "Neighbouring"}
+			]}`))
+			return
+		case "/convert-to-coordinates":
+			if normaliseWords(query.Get("words")) != "filled.count.soap" {
+				rw.WriteHeader(http.StatusBadRequest)
+				_, _ = rw.Write([]byte(`{"error": {"code": "BadWords", "message": "words must be a valid 3 word address"}}`))
+				return
+			}
+			_ = json.NewEncoder(rw).Encode(LocationResponse{Words: "filled.count.soap", Language: "en", Square: square})
+			return
+		}
+
+		assert.Equal(t, "51.520847,-0.195521", query.Get("coordinates"), "the centre of the square is converted")
+		language := query.Get("language")
+		if locale := query.Get("locale"); locale != "" {
+			language = locale
+		}
+		resp := LocationResponse{Words: words[language], Language: query.Get("language"), Locale: query.Get("locale"), Square: square}
+		switch language {
+		case "xx":
+			resp.Square = neighbour
+		case "zz":
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"error": {"code": "BadLanguage", "message": "language must be one of the available languages"}}`))
+			return
+		}
+		_ = json.NewEncoder(rw).Encode(resp)
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts, func() []url.Values {
+		mu.Lock()
+		defer mu.Unlock()
+		return queries
+	}
+}
+
+func TestW3w_Translate(t *testing.T) {
+	tests := map[string]struct {
+		words            string
+		language         string
+		expectedWords    string
+		expectedLanguage string
+		expectedLocale   string
+		expectedErr      error
+	}{
+		"language": {
+			words:            "///Filled.Count.Soap",
+			language:         "de",
+			expectedWords:    "welche.tischtennis.bekannte",
+			expectedLanguage: "de",
+		},
+		"locale": {
+			words:            "filled.count.soap",
+			language:         "mn_cy",
+			expectedWords:    "санхүү.видео.анчин",
+			expectedLanguage: "mn",
+			expectedLocale:   "mn_cy",
+		},
+		"different square": {
+			words:       "filled.count.soap",
+			language:    "xx",
+			expectedErr: ErrSquareMismatch,
+		},
+		"unavailable language": {
+			words:       "filled.count.soap",
+			language:    "zz",
+			expectedErr: ErrBadLanguage,
+		},
+		"unknown words": {
+			words:       "index.home.raft",
+			language:    "de",
+			expectedErr: ErrBadWords,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts, queries := newTranslationServer(t)
+			u, err := url.Parse(ts.URL)
+			assert.NoError(t, err)
+
+			w := NewClient("example-api-key", WithEndpoint(u), WithLocale("mn_la"))
+			got, err := w.Translate(context.Background(), tt.words, tt.language)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, got)
+				return
+			}
+
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tt.expectedWords, got.Words)
+			assert.Equal(t, tt.expectedLanguage, got.Language)
+			assert.Equal(t, tt.expectedLocale, got.Locale)
+			assert.Len(t, queries(), 2)
+		})
+	}
+}
+
+func TestW3w_TranslateAll(t *testing.T) {
+	ts, _ := newTranslationServer(t)
+	u, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	w := NewClient("example-api-key", WithEndpoint(u))
+	translations, err := w.TranslateAll(context.Background(), "filled.count.soap")
+	assert.NoError(t, err)
+
+	var (
+		languages []string
+		words     []string
+	)
+	for _, translation := range translations {
+		languages = append(languages, translation.Language)
+		if translation.Err != nil {
+			words = append(words, "")
+			assert.ErrorIs(t, translation.Err, ErrSquareMismatch)
+			continue
+		}
+		words = append(words, translation.Response.Words)
+	}
+	assert.Equal(t, []string{"en", "de", "mn_la", "mn_cy", "xx"}, languages)
+	assert.Equal(t, []string{"filled.count.soap", "welche.tischtennis.bekannte", "sankhuu.vidio.anchin", "санхүү.видео.анчин", ""}, words)
+
+	_, err = w.TranslateAll(context.Background(), "index.home.raft")
+	assert.ErrorIs(t, err, ErrBadWords)
+}
@@ -2,26 +2,55 @@ package what3words
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net/http"
 	"net/url"
+	"sync/atomic"
 )
 
 const _defaultEndpoint = "https://api.what3words.com/v3"
 
 // What3Words interface defines a set of methods that can be used to interact with the What3Words API.
-// The methods provide functionality for auto-suggesting 3 word addresses, retrieving available languages,
-// converting between 3 word addresses and coordinates, and retrieving a grid section for a given bounding box.
+// The methods provide functionality for auto-suggesting 3 word addresses, reporting selected suggestions,
+// retrieving available languages, converting between 3 word addresses and coordinates,
+// retrieving a grid section for a given bounding box, as JSON or GeoJSON, and translating 3 word addresses between
+// languages, as well as checking the health of the API.
+// Every request accepts CallOptions which take precedence over the client defaults.
 type What3Words interface {
-	AutoSuggest(ctx context.Context, input *AutoSuggestInput) (*AutoSuggestResponse, error)
-	AvailableLanguages(ctx context.Context) ([]Language, error)
-	ConvertTo3wa(ctx context.Context, coordinates *Coordinates) (*LocationResponse, error)
-	ConvertToCoordinates(ctx context.Context, words string) (*LocationResponse, error)
-	GridSection(ctx context.Context, boundingBox *BoundingBox) (*GridSection, error)
+	AutoSuggest(ctx context.Context, input *AutoSuggestInput, opts ...CallOption) (*AutoSuggestResponse, error)
+	AutoSuggestWithCoordinates(ctx context.Context, input *AutoSuggestInput, opts ...CallOption) (*AutoSuggestWithCoordinatesResponse, error)
+	ReportSelection(ctx context.Context, input *AutoSuggestInput, selection Suggestion, rank int, opts ...CallOption) error
+	AvailableLanguages(ctx context.Context, opts ...CallOption) ([]Language, error)
+	ConvertTo3wa(ctx context.Context, coordinates *Coordinates, opts ...CallOption) (*LocationResponse, error)
+	ConvertToCoordinates(ctx context.Context, words string, opts ...CallOption) (*LocationResponse, error)
+	GridSection(ctx context.Context, boundingBox *BoundingBox, opts ...CallOption) (*GridSection, error)
+	GridSectionLarge(ctx context.Context, boundingBox *BoundingBox, opts BatchOptions, callOpts ...CallOption) (*GridSection, error)
+	ConvertTo3waGeoJSON(ctx context.Context, coordinates *Coordinates, opts ...CallOption) (*FeatureCollection, error)
+	ConvertToCoordinatesGeoJSON(ctx context.Context, words string, opts ...CallOption) (*FeatureCollection, error)
+	GridSectionGeoJSON(ctx context.Context, boundingBox *BoundingBox, opts ...CallOption) (*FeatureCollection, error)
+	BatchConvertTo3wa(ctx context.Context, coordinates []Coordinates, opts BatchOptions, callOpts ...CallOption) ([]BatchResult, error)
+	BatchConvertToCoordinates(ctx context.Context, words []string, opts BatchOptions, callOpts ...CallOption) ([]BatchResult, error)
+	Translate(ctx context.Context, words, language string, opts ...CallOption) (*LocationResponse, error)
+	TranslateAll(ctx context.Context, words string, opts ...CallOption) ([]Translation, error)
+	CacheStats() CacheStats
+	KeyStats() []KeyStats
+	Health(ctx context.Context, opts ...CallOption) error
 }
 
 // Language contains a language's ISO 639-1 2-letter code, english name and native name.
+// Languages written in several scripts, such as Mongolian or Kazakh, also list their locales.
 type Language struct {
+	Code       string   `json:"code"`
+	Name       string   `json:"name"`
+	NativeName string   `json:"nativeName"`
+	Locales    []Locale `json:"locales,omitempty"`
+}
+
+// Locale contains the code of a locale, e.g. mn_la for Mongolian in Latin script, and its english and native names.
+// The code can be passed to WithLocale or WithCallLocale.
+type Locale struct {
 	Code       string `json:"code"`
 	Name       string `json:"name"`
 	NativeName string `json:"nativeName"`
@@ -37,7 +66,25 @@ type w3w struct {
 	http     *http.Client
 	apiKey   string
 	language string
+	locale   string
 	endpoint *url.URL
+	retry    *RetryPolicy
+	limiter  *RateLimiter
+	cache    Cache
+	flights  *flightGroup
+	keys     *keyPool
+
+	// certificates, rootCAs and health configure self-hosted Enterprise Suite deployments.
+	certificates []tls.Certificate
+	rootCAs      *x509.CertPool
+	health       *url.URL
+
+	// configErr is the first error configuring the client. Every request fails with it, so that a client missing
+	// settings such as TLS client certificates never sends requests without them.
+	configErr error
+
+	cacheHits   atomic.Uint64
+	cacheMisses atomic.Uint64
 }
 
 // Option is an optional function parameter for the w3w struct
@@ -50,6 +97,14 @@ func WithLanguage(language string) Option {
 	}
 }
 
+// WithLocale is a Functional Option for setting the w3w client locale, for languages with multiple scripts,
+// e.g. mn_la or mn_cy. The locale must belong to the client language.
+func WithLocale(locale string) Option {
+	return func(w *w3w) {
+		w.locale = locale
+	}
+}
+
 // WithEndpoint is a Functional Option for setting the w3w client endpoint.
 func WithEndpoint(endpoint *url.URL) Option {
 	return func(w *w3w) {
@@ -58,8 +113,8 @@ func WithEndpoint(endpoint *url.URL) Option {
 }
 
 // WithHTTPClient is a Functional Option for setting the w3w HTTP client.
-// This option allows you to pass in various http client implementations such as hashicorp/go-retryablehttp
-// which provides automatic retries and exponential backoff.
+// This option allows you to pass in custom http client implementations, for example to configure timeouts
+// or transports. Use WithRetry for automatic retries with exponential backoff.
 func WithHTTPClient(client *http.Client) Option {
 	return func(w *w3w) {
 		w.http = client
@@ -67,6 +122,8 @@ func WithHTTPClient(client *http.Client) Option {
 }
 
 // NewClient creates a new client which can be used to interact with the what3words API.
+// The apiKey may be empty for self-hosted Enterprise Suite deployments which do not require one,
+// in which case no X-Api-Key header is sent.
 func NewClient(apiKey string, opts ...Option) What3Words {
 	w3wURL, _ := url.Parse(_defaultEndpoint)
 
@@ -79,38 +136,72 @@ func NewClient(apiKey string, opts ...Option) What3Words {
 	for _, opt := range opts {
 		opt(w)
 	}
+	if err := w.configureTLS(); err != nil && w.configErr == nil {
+		w.configErr = err
+	}
 
 	return w
 }
 
 // ConvertTo3wa This function will convert a latitude and longitude to a 3 word address, in the language of your choice.
 // It also returns country, the bounds of the grid square, a nearby place (such as a local town) and a link to our map site.
-func (w *w3w) ConvertTo3wa(ctx context.Context, coordinates *Coordinates) (*LocationResponse, error) {
-	u := w.endpoint.JoinPath("/convert-to-3wa")
+func (w *w3w) ConvertTo3wa(ctx context.Context, coordinates *Coordinates, opts ...CallOption) (*LocationResponse, error) {
+	if coordinates == nil {
+		return nil, fmt.Errorf("converting coordinates to 3 Word Address: %w", &ValidationError{Field: "coordinates", Message: "must be specified"})
+	}
+	if err := coordinates.Validate(); err != nil {
+		return nil, fmt.Errorf("converting coordinates to 3 Word Address: %w", err)
+	}
+
+	c := w.newCall(opts)
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	if c.cached() {
+		if resp, ok := w.cachedSquare(c.cacheLanguage(), *coordinates); ok {
+			return resp, nil
+		}
+	}
+
+	u := w.endpointURL("convert-to-3wa")
 	query := u.Query()
 	query.Set("coordinates", coordinates.ToString())
-	query.Set("language", w.language)
+	c.setLanguage(query)
+	c.setFormat(query)
 	u.RawQuery = query.Encode()
 
 	var resp LocationResponse
-	if err := w.request(ctx, u.String(), &resp); err != nil {
+	if err := w.request(ctx, u, c, &resp); err != nil {
 		return nil, fmt.Errorf("converting coordinates to 3 Word Address: %w", err)
 	}
+	w.cacheLocation(c.cacheLanguage(), c.cacheLanguage(), &resp)
 
 	return &resp, nil
 }
 
 // GridSection returns a section of the What3Words 3m x 3m grid as a set of horizontal and vertical lines
 // covering the requested area, which can then be drawn onto a map.
-func (w *w3w) GridSection(ctx context.Context, box *BoundingBox) (*GridSection, error) {
-	u := w.endpoint.JoinPath("/grid-section")
+func (w *w3w) GridSection(ctx context.Context, box *BoundingBox, opts ...CallOption) (*GridSection, error) {
+	if box == nil {
+		return nil, fmt.Errorf("retrieving grid section: %w", &ValidationError{Field: "bounding-box", Message: "must be specified"})
+	}
+	if err := box.Validate(); err != nil {
+		return nil, fmt.Errorf("retrieving grid section: %w", err)
+	}
+
+	c := w.newCall(opts)
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	u := w.endpointURL("grid-section")
 	query := u.Query()
 	query.Set("bounding-box", box.ToString())
-	query.Set("language", w.language)
+	c.setLanguage(query)
+	c.setFormat(query)
 	u.RawQuery = query.Encode()
 
 	var resp GridSection
-	if err := w.request(ctx, u.String(), &resp); err != nil {
+	if err := w.request(ctx, u, c, &resp); err != nil {
 		return nil, fmt.Errorf("retrieving grid section: %w", err)
 	}
 
@@ -119,30 +210,56 @@ func (w *w3w) GridSection(ctx context.Context, box *BoundingBox) (*GridSection,
 
 // ConvertToCoordinates converts a 3 word address to a latitude and longitude. It also returns country,
 // the bounds of the grid square, the nearest place (such as a local town) and a link to the What3Words map site.
-func (w *w3w) ConvertToCoordinates(ctx context.Context, words string) (*LocationResponse, error) {
-	u := w.endpoint.JoinPath("/convert-to-coordinates")
+func (w *w3w) ConvertToCoordinates(ctx context.Context, words string, opts ...CallOption) (*LocationResponse, error) {
+	if !IsPossible3wa(words) {
+		return nil, fmt.Errorf("converting w3w to coordinates: %w", &ValidationError{
+			Field:   "words",
+			Message: fmt.Sprintf("%q is not a valid 3 word address", words),
+		})
+	}
+
+	c := w.newCall(opts)
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	var cached LocationResponse
+	if c.cached() && w.cacheGet(wordsCacheKey(c.cacheLanguage(), words), &cached) {
+		return &cached, nil
+	}
+
+	u := w.endpointURL("convert-to-coordinates")
 	query := u.Query()
 	query.Set("words", words)
-	query.Set("language", w.language)
+	c.setLanguage(query)
+	c.setFormat(query)
 	u.RawQuery = query.Encode()
 
 	var resp LocationResponse
-	if err := w.request(ctx, u.String(), &resp); err != nil {
+	if err := w.request(ctx, u, c, &resp); err != nil {
 		return nil, fmt.Errorf("converting w3w to coordinates: %w", err)
 	}
+	w.cacheLocation(c.cacheLanguage(), c.wordsLanguage(&resp), &resp)
 
 	return &resp, nil
 }
 
 // AvailableLanguages Retrieves a list of all available 3 word address languages,
-// including the ISO 3166-1 alpha-2 2-letter code, english name and native name.
-func (w *w3w) AvailableLanguages(ctx context.Context) ([]Language, error) {
-	u := w.endpoint.JoinPath("/available-languages").String()
+// including the ISO 3166-1 alpha-2 2-letter code, english name, native name and locales.
+func (w *w3w) AvailableLanguages(ctx context.Context, opts ...CallOption) ([]Language, error) {
+	c := w.newCall(opts)
+	ctx, cancel := c.context(ctx)
+	defer cancel()
 
 	var resp AvailableLanguages
-	if err := w.request(ctx, u, &resp); err != nil {
+	if c.cached() && w.cacheGet("available-languages", &resp) {
+		return resp.Languages, nil
+	}
+
+	u := w.endpointURL("available-languages")
+	if err := w.request(ctx, u, c, &resp); err != nil {
 		return nil, fmt.Errorf("retrieving available languages: %w", err)
 	}
+	w.cacheSet("available-languages", &resp, _languagesCacheTTL)
 
 	return resp.Languages, nil
 }
@@ -178,6 +178,35 @@ func TestW3w_AvailableLanguages(t *testing.T) {
 				}`),
 			},
 		},
+		"successfully retrieve languages with locales": {
+			expected: []Language{
+				{
+					Code:       "mn",
+					Name:       "Mongolian",
+					NativeName: "Монгол",
+					Locales: []Locale{
+						{Code: "mn_la", Name: "Mongolian (Latin)", NativeName: "Mongol (Latin)"},
+						{Code: "mn_cy", Name: "Mongolian (Cyrillic)", NativeName: "Монгол (Кирилл)"},
+					},
+				},
+			},
+			response: response{
+				statusCode: http.StatusOK,
+				body: []byte(`{
+				  "languages": [
+					{
+					  "nativeName": "Монгол",
+					  "code": "mn",
+					  "name": "Mongolian",
+					  "locales": [
+						{"nativeName": "Mongol (Latin)", "code": "mn_la", "name": "Mongolian (Latin)"},
+						{"nativeName": "Монгол (Кирилл)", "code": "mn_cy", "name": "Mongolian (Cyrillic)"}
+					  ]
+					}
+				  ]
+				}`),
+			},
+		},
 		"error retrieving available languages": {
 			expectedError: "retrieving available languages",
 			response: response{
@@ -273,6 +302,13 @@ func TestW3w_ConvertTo3wa(t *testing.T) {
 				}`),
 			},
 		},
+		"invalid coordinates are not sent": {
+			expectedError: "converting coordinates to 3 Word Address: invalid coordinates: latitude -151.2093 must be between -90 and 90",
+			coordinates:   Coordinates{Lat: -151.2093, Lng: -33.8688},
+			response: response{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
 		"error converting coordinates to 3wa": {
 			expectedError: "converting coordinates to 3 Word Address",
 			response: response{
@@ -382,6 +418,18 @@ func TestW3w_GridSection(t *testing.T) {
 				}`),
 			},
 		},
+		"invalid bounding box is not sent": {
+			expectedError: "retrieving grid section: invalid bounding-box: south latitude 52.208867 must not be greater than north latitude 52.207988",
+			boundingBox: BoundingBox{
+				SouthLat: 52.208867,
+				WestLng:  0.116126,
+				NorthLat: 52.207988,
+				EastLng:  0.117540,
+			},
+			response: response{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
 		"error retrieving grid section": {
 			expectedError: "retrieving grid section",
 			response: response{
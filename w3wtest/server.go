@@ -0,0 +1,428 @@
+// Package w3wtest provides an in-process fake what3words API server for testing code which uses the what3words client.
+//
+// The fake serves the v3 endpoints from seeded fixtures, can return the real what3words error payloads, add latency,
+// and records every request it receives:
+//
+//	fake := w3wtest.NewServer()
+//	defer fake.Close()
+//
+//	fake.AddLocation(what3words.LocationResponse{Words: "filled.count.soap", ...})
+//	client := what3words.NewClient("test-key", what3words.WithEndpoint(fake.URL()))
+package w3wtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+// Endpoint names served by the fake server.
+const (
+	EndpointConvertTo3wa               = "convert-to-3wa"
+	EndpointConvertToCoordinates       = "convert-to-coordinates"
+	EndpointAutoSuggest                = "autosuggest"
+	EndpointAutoSuggestWithCoordinates = "autosuggest-with-coordinates"
+	EndpointAutoSuggestSelection       = "autosuggest-selection"
+	EndpointGridSection                = "grid-section"
+	EndpointAvailableLanguages         = "available-languages"
+)
+
+// Request is a request received by the fake server.
+type Request struct {
+	// Endpoint is the name of the endpoint that was called, e.g. convert-to-3wa.
+	Endpoint string
+
+	// Query contains the query parameters of the request.
+	Query url.Values
+
+	// Header contains the request headers, including X-Api-Key.
+	Header http.Header
+}
+
+// Server is a fake what3words API server. It is safe to seed fixtures while requests are being served.
+type Server struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	locations []what3words.LocationResponse
+	languages []what3words.Language
+	grids     map[string]what3words.GridSection
+	errors    map[string]*what3words.APIError
+	latency   time.Duration
+	requests  []Request
+}
+
+// NewServer starts a fake what3words API server. The server must be closed with Close when it is no longer needed.
+func NewServer() *Server {
+	s := &Server{
+		languages: []what3words.Language{{Code: "en", Name: "English", NativeName: "English"}},
+		grids:     make(map[string]what3words.GridSection),
+		errors:    make(map[string]*what3words.APIError),
+	}
+	s.server = httptest.NewServer(s)
+	return s
+}
+
+// URL returns the endpoint of the fake server, to be passed to what3words.WithEndpoint.
+func (s *Server) URL() *url.URL {
+	u, _ := url.Parse(s.server.URL)
+	return u
+}
+
+// Close shuts down the fake server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// AddLocation seeds a location which is returned by convert-to-coordinates for its words, by convert-to-3wa for
+// any coordinates inside its square, and by autosuggest for any input its words start with.
+func (s *Server) AddLocation(location what3words.LocationResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.locations = append(s.locations, location)
+}
+
+// SetLanguages replaces the languages returned by available-languages. Defaults to English only.
+func (s *Server) SetLanguages(languages ...what3words.Language) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.languages = languages
+}
+
+// AddGridSection seeds the grid section returned by grid-section for the bounding box.
+func (s *Server) AddGridSection(box what3words.BoundingBox, grid what3words.GridSection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.grids[box.ToString()] = grid
+}
+
+// SetError makes every request to the endpoint fail with the what3words error payload for the code and message,
+// e.g. SetError(EndpointConvertToCoordinates, http.StatusPaymentRequired, what3words.ErrQuotaExceeded, "Quota Exceeded").
+func (s *Server) SetError(endpoint string, statusCode int, code what3words.ErrorCode, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors[endpoint] = &what3words.APIError{StatusCode: statusCode, Code: code, Message: message, Endpoint: endpoint}
+}
+
+// ClearError removes an error set with SetError.
+func (s *Server) ClearError(endpoint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.errors, endpoint)
+}
+
+// SetLatency delays every response by the given duration.
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = latency
+}
+
+// Requests returns the requests received by the server, in the order they were received.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// ServeHTTP serves a request to the fake what3words API.
+func (s *Server) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	endpoint := path.Base(r.URL.Path)
+	query := r.URL.Query()
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Endpoint: endpoint, Query: query, Header: r.Header.Clone()})
+	latency := s.latency
+	apiErr := s.errors[endpoint]
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if apiErr != nil {
+		writeError(rw, apiErr.StatusCode, apiErr.Code, apiErr.Message)
+		return
+	}
+
+	switch endpoint {
+	case EndpointConvertTo3wa:
+		s.convertTo3wa(rw, query)
+	case EndpointConvertToCoordinates:
+		s.convertToCoordinates(rw, query)
+	case EndpointAutoSuggest:
+		s.autoSuggest(rw, query, false)
+	case EndpointAutoSuggestWithCoordinates:
+		s.autoSuggest(rw, query, true)
+	case EndpointAutoSuggestSelection:
+		rw.WriteHeader(http.StatusOK)
+	case EndpointGridSection:
+		s.gridSection(rw, query)
+	case EndpointAvailableLanguages:
+		s.mu.Lock()
+		languages := what3words.AvailableLanguages{Languages: s.languages}
+		s.mu.Unlock()
+		writeJSON(rw, languages)
+	default:
+		writeError(rw, http.StatusNotFound, "NotFound", fmt.Sprintf("endpoint %s not found", endpoint))
+	}
+}
+
+func (s *Server) convertTo3wa(rw http.ResponseWriter, query url.Values) {
+	geoJSON, ok := parseFormat(rw, query)
+	if !ok {
+		return
+	}
+	coordinates, err := parseCoordinates(query.Get("coordinates"))
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, what3words.ErrBadCoordinates, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, location := range s.locations {
+		sw, ne := location.Square.Southwest, location.Square.Northeast
+		if coordinates.Lat >= sw.Lat && coordinates.Lat <= ne.Lat && coordinates.Lng >= sw.Lng && coordinates.Lng <= ne.Lng {
+			writeLocation(rw, location, geoJSON)
+			return
+		}
+	}
+
+	writeError(rw, http.StatusBadRequest, what3words.ErrBadCoordinates, "no location seeded for coordinates "+query.Get("coordinates"))
+}
+
+func (s *Server) convertToCoordinates(rw http.ResponseWriter, query url.Values) {
+	geoJSON, ok := parseFormat(rw, query)
+	if !ok {
+		return
+	}
+	words := strings.TrimLeft(query.Get("words"), "/")
+	if words == "" {
+		writeError(rw, http.StatusBadRequest, what3words.ErrMissingWords, "words must be specified")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, location := range s.locations {
+		if strings.EqualFold(location.Words, words) {
+			writeLocation(rw, location, geoJSON)
+			return
+		}
+	}
+
+	writeError(rw, http.StatusBadRequest, what3words.ErrBadWords,
+		"words must be a valid 3 word address, such as filled.count.soap or ///filled.count.soap")
+}
+
+func (s *Server) autoSuggest(rw http.ResponseWriter, query url.Values, withCoordinates bool) {
+	input := strings.ToLower(query.Get("input"))
+	if input == "" {
+		writeError(rw, http.StatusBadRequest, what3words.ErrMissingInput, "input must be specified")
+		return
+	}
+
+	nResults := 3
+	if n := query.Get("n-results"); n != "" {
+		var err error
+		if nResults, err = strconv.Atoi(n); err != nil || nResults < 1 || nResults > 100 {
+			writeError(rw, http.StatusBadRequest, what3words.ErrBadNResults, "n-results must be between 1 and 100")
+			return
+		}
+	}
+
+	var countries []string
+	if clip := query.Get("clip-to-country"); clip != "" {
+		countries = strings.Split(clip, ",")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	suggestions := make([]what3words.SuggestionWithCoordinates, 0, nResults)
+	for _, location := range s.locations {
+		if len(suggestions) == nResults {
+			break
+		}
+		if !strings.HasPrefix(strings.ToLower(location.Words), input) || !containsFold(countries, location.Country) {
+			continue
+		}
+
+		suggestions = append(suggestions, what3words.SuggestionWithCoordinates{
+			Suggestion: what3words.Suggestion{
+				Country:      location.Country,
+				NearestPlace: location.NearestPlace,
+				Words:        location.Words,
+				Rank:         len(suggestions) + 1,
+				Language:     location.Language,
+			},
+			Coordinates: location.Coordinates,
+			Square:      location.Square,
+			Map:         location.Map,
+		})
+	}
+
+	if withCoordinates {
+		writeJSON(rw, what3words.AutoSuggestWithCoordinatesResponse{Suggestions: suggestions})
+		return
+	}
+
+	resp := what3words.AutoSuggestResponse{Suggestions: make([]what3words.Suggestion, 0, len(suggestions))}
+	for _, suggestion := range suggestions {
+		resp.Suggestions = append(resp.Suggestions, suggestion.Suggestion)
+	}
+	writeJSON(rw, resp)
+}
+
+func (s *Server) gridSection(rw http.ResponseWriter, query url.Values) {
+	geoJSON, ok := parseFormat(rw, query)
+	if !ok {
+		return
+	}
+	box := query.Get("bounding-box")
+	if box == "" {
+		writeError(rw, http.StatusBadRequest, what3words.ErrMissingBoundingBox, "bounding-box must be specified")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	grid, ok := s.grids[box]
+	if !ok {
+		writeError(rw, http.StatusBadRequest, what3words.ErrBadBoundingBox, "no grid section seeded for bounding-box "+box)
+		return
+	}
+	if !geoJSON {
+		writeJSON(rw, grid)
+		return
+	}
+
+	lines := make([][][2]float64, 0, len(grid.Lines))
+	for _, line := range grid.Lines {
+		lines = append(lines, [][2]float64{{line.Start.Lng, line.Start.Lat}, {line.End.Lng, line.End.Lat}})
+	}
+	writeJSON(rw, featureCollection(map[string]interface{}{
+		"type":       "Feature",
+		"geometry":   map[string]interface{}{"type": "MultiLineString", "coordinates": lines},
+		"properties": map[string]interface{}{},
+	}))
+}
+
+// parseFormat parses the format parameter, reporting whether GeoJSON was requested. It writes the BadFormat error and
+// returns false if the format is not supported.
+func parseFormat(rw http.ResponseWriter, query url.Values) (geoJSON, ok bool) {
+	switch query.Get("format") {
+	case "", "json":
+		return false, true
+	case "geojson":
+		return true, true
+	default:
+		writeError(rw, http.StatusBadRequest, what3words.ErrBadFormat, "format must be json or geojson")
+		return false, false
+	}
+}
+
+// writeLocation writes the location as JSON, or in the geojson format of the API, which is a Point at the coordinates
+// with the grid square as its bbox and the other fields as properties.
+func writeLocation(rw http.ResponseWriter, location what3words.LocationResponse, geoJSON bool) {
+	if !geoJSON {
+		writeJSON(rw, location)
+		return
+	}
+
+	sw, ne := location.Square.Southwest, location.Square.Northeast
+	properties := map[string]interface{}{
+		"country":      location.Country,
+		"nearestPlace": location.NearestPlace,
+		"words":        location.Words,
+		"language":     location.Language,
+		"map":          location.Map,
+	}
+	if location.Locale != "" {
+		properties["locale"] = location.Locale
+	}
+	writeJSON(rw, featureCollection(map[string]interface{}{
+		"type":       "Feature",
+		"bbox":       []float64{sw.Lng, sw.Lat, ne.Lng, ne.Lat},
+		"geometry":   map[string]interface{}{"type": "Point", "coordinates": []float64{location.Coordinates.Lng, location.Coordinates.Lat}},
+		"properties": properties,
+	}))
+}
+
+// featureCollection returns a GeoJSON FeatureCollection containing the feature.
+func featureCollection(feature map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"type": "FeatureCollection", "features": []interface{}{feature}}
+}
+
+// parseCoordinates parses coordinates in the "lat,lng" format used by the what3words API.
+func parseCoordinates(value string) (what3words.Coordinates, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return what3words.Coordinates{}, fmt.Errorf("coordinates %q must be in the format lat,lng", value)
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return what3words.Coordinates{}, fmt.Errorf("invalid latitude %q", parts[0])
+	}
+
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return what3words.Coordinates{}, fmt.Errorf("invalid longitude %q", parts[1])
+	}
+
+	return what3words.Coordinates{Lat: lat, Lng: lng}, nil
+}
+
+func containsFold(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(rw http.ResponseWriter, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(v)
+}
+
+// writeError writes the what3words error payload for the code and message.
+func writeError(rw http.ResponseWriter, statusCode int, code what3words.ErrorCode, message string) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(statusCode)
+	_ = json.NewEncoder(rw).Encode(map[string]interface{}{
+		"error": map[string]string{
+			"code":    string(code),
+			"message": message,
+		},
+	})
+}
@@ -0,0 +1,203 @@
+package w3wtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	what3words "github.com/henrwal/w3w-go-wrapper"
+)
+
+var filledCountSoap = what3words.LocationResponse{
+	Coordinates:  what3words.Coordinates{Lat: 51.520847, Lng: -0.195521},
+	Country:      "GB",
+	Language:     "en",
+	Map:          "https://w3w.co/filled.count.soap",
+	NearestPlace: "Bayswater, London",
+	Square: what3words.Square{
+		Northeast: what3words.Coordinates{Lat: 51.52086, Lng: -0.195499},
+		Southwest: what3words.Coordinates{Lat: 51.520833, Lng: -0.195543},
+	},
+	Words: "filled.count.soap",
+}
+
+func newTestClient(t *testing.T) (*Server, what3words.What3Words) {
+	fake := NewServer()
+	t.Cleanup(fake.Close)
+
+	fake.AddLocation(filledCountSoap)
+	return fake, what3words.NewClient("test-key", what3words.WithEndpoint(fake.URL()))
+}
+
+func TestServer_Conversions(t *testing.T) {
+	_, client := newTestClient(t)
+	ctx := context.Background()
+
+	got, err := client.ConvertToCoordinates(ctx, "///Filled.Count.Soap")
+	assert.NoError(t, err)
+	assert.Equal(t, &filledCountSoap, got)
+
+	got, err = client.ConvertTo3wa(ctx, &what3words.Coordinates{Lat: 51.52085, Lng: -0.19552})
+	assert.NoError(t, err)
+	assert.Equal(t, &filledCountSoap, got)
+
+	_, err = client.ConvertToCoordinates(ctx, "index.home.raft")
+	assert.ErrorIs(t, err, what3words.ErrBadWords)
+
+	_, err = client.ConvertTo3wa(ctx, &what3words.Coordinates{Lat: 1, Lng: 1})
+	assert.ErrorIs(t, err, what3words.ErrBadCoordinates)
+}
+
+func TestServer_GeoJSON(t *testing.T) {
+	fake, client := newTestClient(t)
+	ctx := context.Background()
+
+	box := &what3words.BoundingBox{SouthLat: 52.207988, WestLng: 0.116126, NorthLat: 52.208867, EastLng: 0.117540}
+	grid := what3words.GridSection{Lines: []what3words.GridLine{{
+		Start: what3words.Coordinates{Lat: 52.20801, Lng: 0.116126},
+		End:   what3words.Coordinates{Lat: 52.20801, Lng: 0.11754},
+	}}}
+	fake.AddGridSection(*box, grid)
+
+	var body []byte
+	got, err := client.ConvertToCoordinates(ctx, "filled.count.soap", what3words.WithCallFormat(what3words.FormatGeoJSON, &body))
+	assert.NoError(t, err)
+	assert.Equal(t, &filledCountSoap, got)
+	assert.Contains(t, string(body), `"type":"Point"`)
+
+	got, err = client.ConvertTo3wa(ctx, &filledCountSoap.Coordinates, what3words.WithCallFormat(what3words.FormatGeoJSON, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, &filledCountSoap, got)
+
+	gotGrid, err := client.GridSection(ctx, box, what3words.WithCallFormat(what3words.FormatGeoJSON, &body))
+	assert.NoError(t, err)
+	assert.Equal(t, &grid, gotGrid)
+	assert.Contains(t, string(body), `"type":"MultiLineString"`)
+
+	_, err = client.GridSection(ctx, box, what3words.WithCallFormat("xml", nil))
+	assert.ErrorIs(t, err, what3words.ErrBadFormat)
+}
+
+func TestServer_AutoSuggest(t *testing.T) {
+	fake, client := newTestClient(t)
+	fake.AddLocation(what3words.LocationResponse{Words: "filled.count.soaps", Country: "US", Language: "en"})
+	fake.AddLocation(what3words.LocationResponse{Words: "index.home.raft", Country: "GB", Language: "en"})
+
+	tests := map[string]struct {
+		input    *what3words.AutoSuggestInput
+		expected []string
+	}{
+		"matches by prefix": {
+			input:    &what3words.AutoSuggestInput{Words: "filled.count.so"},
+			expected: []string{"filled.count.soap", "filled.count.soaps"},
+		},
+		"clips to country": {
+			input:    &what3words.AutoSuggestInput{Words: "filled.count.so", ClipToCountry: []string{"us"}},
+			expected: []string{"filled.count.soaps"},
+		},
+		"limits results": {
+			input:    &what3words.AutoSuggestInput{Words: "filled.count.so", NResults: 1},
+			expected: []string{"filled.count.soap"},
+		},
+		"no matches": {
+			input:    &what3words.AutoSuggestInput{Words: "daring.lion.race"},
+			expected: []string{},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := client.AutoSuggest(context.Background(), tt.input)
+			assert.NoError(t, err)
+
+			words := []string{}
+			for i, suggestion := range resp.Suggestions {
+				assert.Equal(t, i+1, suggestion.Rank)
+				words = append(words, suggestion.Words)
+			}
+			assert.Equal(t, tt.expected, words)
+		})
+	}
+
+	resp, err := client.AutoSuggestWithCoordinates(context.Background(), &what3words.AutoSuggestInput{Words: "filled.count.soap"})
+	assert.NoError(t, err)
+	assert.Equal(t, filledCountSoap.Coordinates, resp.Suggestions[0].Coordinates)
+	assert.Equal(t, filledCountSoap.Square, resp.Suggestions[0].Square)
+}
+
+func TestServer_GridSectionAndLanguages(t *testing.T) {
+	fake, client := newTestClient(t)
+	ctx := context.Background()
+
+	box := &what3words.BoundingBox{SouthLat: 52.207988, WestLng: 0.116126, NorthLat: 52.208867, EastLng: 0.117540}
+	grid := what3words.GridSection{Lines: []what3words.GridLine{{
+		Start: what3words.Coordinates{Lat: 52.20801, Lng: 0.116126},
+		End:   what3words.Coordinates{Lat: 52.20801, Lng: 0.11754},
+	}}}
+	fake.AddGridSection(*box, grid)
+	fake.SetLanguages(what3words.Language{Code: "de", Name: "German", NativeName: "Deutsch"})
+
+	gotGrid, err := client.GridSection(ctx, box)
+	assert.NoError(t, err)
+	assert.Equal(t, &grid, gotGrid)
+
+	languages, err := client.AvailableLanguages(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []what3words.Language{{Code: "de", Name: "German", NativeName: "Deutsch"}}, languages)
+
+	_, err = client.GridSection(ctx, &what3words.BoundingBox{})
+	assert.ErrorIs(t, err, what3words.ErrBadBoundingBox)
+}
+
+func TestServer_SetError(t *testing.T) {
+	fake, client := newTestClient(t)
+	fake.SetError(EndpointConvertToCoordinates, http.StatusPaymentRequired, what3words.ErrQuotaExceeded, "Quota Exceeded")
+
+	_, err := client.ConvertToCoordinates(context.Background(), "filled.count.soap")
+	assert.ErrorIs(t, err, what3words.ErrQuotaExceeded)
+
+	var apiErr *what3words.APIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, http.StatusPaymentRequired, apiErr.StatusCode)
+		assert.Equal(t, "Quota Exceeded", apiErr.Message)
+	}
+
+	fake.ClearError(EndpointConvertToCoordinates)
+	_, err = client.ConvertToCoordinates(context.Background(), "filled.count.soap")
+	assert.NoError(t, err)
+}
+
+func TestServer_SetLatency(t *testing.T) {
+	fake, client := newTestClient(t)
+	fake.SetLatency(200 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := client.ConvertToCoordinates(ctx, "filled.count.soap")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestServer_Requests(t *testing.T) {
+	fake, client := newTestClient(t)
+
+	_, err := client.ConvertToCoordinates(context.Background(), "filled.count.soap")
+	assert.NoError(t, err)
+	resp, err := client.AutoSuggest(context.Background(), &what3words.AutoSuggestInput{Words: "filled.count.so"})
+	assert.NoError(t, err)
+	err = client.ReportSelection(context.Background(), &what3words.AutoSuggestInput{Words: "filled.count.so"}, resp.Suggestions[0], 1)
+	assert.NoError(t, err)
+
+	requests := fake.Requests()
+	if assert.Len(t, requests, 3) {
+		assert.Equal(t, EndpointConvertToCoordinates, requests[0].Endpoint)
+		assert.Equal(t, "filled.count.soap", requests[0].Query.Get("words"))
+		assert.Equal(t, "test-key", requests[0].Header.Get("X-Api-Key"))
+		assert.Equal(t, EndpointAutoSuggest, requests[1].Endpoint)
+		assert.Equal(t, EndpointAutoSuggestSelection, requests[2].Endpoint)
+		assert.Equal(t, "filled.count.soap", requests[2].Query.Get("selection"))
+	}
+}
@@ -0,0 +1,51 @@
+package what3words
+
+import (
+	"regexp"
+)
+
+// The patterns below follow the regular expressions published by what3words for detecting 3 word addresses.
+// Words may be written in any script, so a word is any run of characters which are not digits, whitespace or
+// ASCII punctuation, and words are separated by a full stop in one of the scripts what3words supports.
+const (
+	_wordPattern      = "[^0-9`~!@#$%^&*()+\\-_=\\[{\\]}\\\\|'<,.>?/\";:£§º©®\\s\\p{Z}]+"
+	_separatorPattern = "[.｡。･・︒។։။۔።।]"
+
+	// _spacedWordPattern matches a word in languages such as Vietnamese, where a single word can contain spaces.
+	_spacedWordPattern = _wordPattern + "(?:[\\x{0020}\\x{00A0}]" + _wordPattern + "){1,3}"
+
+	// _didYouMeanSeparatorPattern matches the separators commonly typed or misheard in place of a full stop.
+	_didYouMeanSeparatorPattern = "[.\\x{FF61}\\x{3002}\\x{FF65}\\x{30FB}\\x{FE12}\\x{17D4}\\x{0964}\\x{1362}:။^_۔։ ,\\\\/+'&;|\\x{3000}\\-]{1,2}"
+)
+
+var (
+	possible3waRegexp = regexp.MustCompile("^/*(?:" +
+		_wordPattern + _separatorPattern + _wordPattern + _separatorPattern + _wordPattern + "|" +
+		_spacedWordPattern + _separatorPattern + _spacedWordPattern + _separatorPattern + _spacedWordPattern +
+		")$")
+
+	find3waRegexp = regexp.MustCompile(_wordPattern + _separatorPattern + _wordPattern + _separatorPattern + _wordPattern)
+
+	didYouMean3waRegexp = regexp.MustCompile("^/*" +
+		_wordPattern + _didYouMeanSeparatorPattern + _wordPattern + _didYouMeanSeparatorPattern + _wordPattern + "$")
+)
+
+// IsPossible3wa reports whether text has the syntax of a 3 word address, such as filled.count.soap
+// or ///filled.count.soap. It does not check that the words are a real 3 word address, which can only be
+// done with ConvertToCoordinates.
+func IsPossible3wa(text string) bool {
+	return possible3waRegexp.MatchString(text)
+}
+
+// FindPossible3wa returns every substring of text which has the syntax of a 3 word address, for example
+// to find 3 word addresses in an SMS or delivery note. It returns nil if there are none.
+func FindPossible3wa(text string) []string {
+	return find3waRegexp.FindAllString(text, -1)
+}
+
+// DidYouMean3wa reports whether text looks like a 3 word address written with the wrong separators,
+// such as "filled count soap" or "filled-count-soap", so that the user can be asked if they meant
+// filled.count.soap. It also reports true for text which IsPossible3wa.
+func DidYouMean3wa(text string) bool {
+	return didYouMean3waRegexp.MatchString(text)
+}
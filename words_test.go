@@ -0,0 +1,100 @@
+package what3words
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPossible3wa(t *testing.T) {
+	tests := map[string]struct {
+		text     string
+		expected bool
+	}{
+		"english 3 word address":          {text: "filled.count.soap", expected: true},
+		"with slashes prefix":             {text: "///filled.count.soap", expected: true},
+		"partial third word":              {text: "plan.clips.a", expected: true},
+		"german with umlaut":              {text: "dösend.geprüft.fächer", expected: true},
+		"hindi devanagari":                {text: "डोलना.पीसना.संभाला", expected: true},
+		"japanese with ideographic stops": {text: "こくさい。ていか。かざす", expected: true},
+		"chinese halfwidth full stop":     {text: "产权｡绝缘｡墨镜", expected: true},
+		"vietnamese words with spaces":    {text: "nước bơi.bạn hát.hành lý", expected: true},
+		"only two words":                  {text: "filled.count", expected: false},
+		"four words":                      {text: "filled.count.soap.bar", expected: false},
+		"spaces instead of dots":          {text: "filled count soap", expected: false},
+		"contains digits":                 {text: "filled.count.s0ap", expected: false},
+		"trailing separator":              {text: "filled.count.", expected: false},
+		"empty":                           {text: "", expected: false},
+		"surrounded by text":              {text: "go to filled.count.soap now", expected: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsPossible3wa(tt.text))
+		})
+	}
+}
+
+func TestFindPossible3wa(t *testing.T) {
+	tests := map[string]struct {
+		text     string
+		expected []string
+	}{
+		"single address in text": {
+			text:     "Please leave the parcel at ///filled.count.soap by the door",
+			expected: []string{"filled.count.soap"},
+		},
+		"multiple addresses": {
+			text:     "Pick up at index.home.raft, drop off at daring.lion.race.",
+			expected: []string{"index.home.raft", "daring.lion.race"},
+		},
+		"non latin script": {
+			text:     "住所は こくさい。ていか。かざす です",
+			expected: []string{"こくさい。ていか。かざす"},
+		},
+		"no addresses": {
+			text:     "Call me on 07700 900123 when you arrive.",
+			expected: nil,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FindPossible3wa(tt.text))
+		})
+	}
+}
+
+func TestDidYouMean3wa(t *testing.T) {
+	tests := map[string]struct {
+		text     string
+		expected bool
+	}{
+		"separated by spaces":                {text: "filled count soap", expected: true},
+		"separated by hyphens":               {text: "filled-count-soap", expected: true},
+		"separated by commas and spaces":     {text: "filled, count, soap", expected: true},
+		"separated by ideographic space":     {text: "filled　count　soap", expected: true},
+		"valid 3 word address":               {text: "filled.count.soap", expected: true},
+		"only two words":                     {text: "filled count", expected: false},
+		"too many separators between words":  {text: "filled - count - soap", expected: false},
+		"contains digits":                    {text: "filled count 50ap", expected: false},
+		"separated by slashes and backslash": {text: "filled/count\\soap", expected: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DidYouMean3wa(tt.text))
+		})
+	}
+}
+
+func TestW3w_ConvertToCoordinatesValidation(t *testing.T) {
+	// The endpoint is unreachable, so the request must be rejected before it is sent.
+	w := NewClient("example-api-key", WithEndpoint(&url.URL{Scheme: "http", Host: "127.0.0.1:0"}))
+
+	_, err := w.ConvertToCoordinates(context.Background(), "filled.count")
+
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "words", validationErr.Field)
+	assert.ErrorContains(t, err, "converting w3w to coordinates")
+}